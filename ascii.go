@@ -0,0 +1,109 @@
+package confusablehomoglyphs
+
+import (
+	"math/bits"
+	"unicode/utf8"
+)
+
+// Most strings are mostly ASCII: the ASCII characters are looked up in
+// precomputed tables rather than in the confusables data, and stretches of
+// them are resolved once per script rather than once per character.
+
+// asciiConfusable is the confusables data of an ASCII character.
+type asciiConfusable struct {
+	script Script
+	// homoglyphs are the homoglyphs of the character, nil if it is not
+	// confusable, and scripts the scripts of their characters.
+	homoglyphs []Homoglyph
+	scripts    ScriptSet
+	// sequences are the confusable sequences starting with the character,
+	// as in confusables.sequences, and next the set of their second bytes.
+	sequences []string
+	next      [4]uint64
+}
+
+// withASCII returns d with the confusables data of the ASCII characters
+// for the scripts data s, d itself if it already has it.
+func (d *confusables) withASCII(s *scriptData) *confusables {
+	if d.asciiScripts == s {
+		return d
+	}
+	data := *d
+	data.ascii = d.buildASCII(s)
+	data.asciiScripts = s
+	return &data
+}
+
+// buildASCII returns the confusables data of the ASCII characters, with the
+// scripts from s.
+func (d *confusables) buildASCII(s *scriptData) [utf8.RuneSelf]asciiConfusable {
+	var ascii [utf8.RuneSelf]asciiConfusable
+	for c := range ascii {
+		ascii[c].script = s.scriptOf(rune(c))
+		if refs, ok := d.index.lookupRune(rune(c)); ok {
+			ascii[c].homoglyphs = d.index.materialize(refs)
+			for _, h := range ascii[c].homoglyphs {
+				for _, chr := range h.C {
+					ascii[c].scripts.Add(s.scriptOf(chr))
+				}
+			}
+		}
+		ascii[c].sequences = d.sequencesOf(rune(c))
+		for _, s := range ascii[c].sequences {
+			ascii[c].next[s[1]/64] |= 1 << (s[1] % 64)
+		}
+	}
+	return ascii
+}
+
+// asciiSafe checks if the ASCII character str starts with is neither
+// confusable with characters from preferredScripts, as per result, nor the
+// start of a confusable sequence of str.
+func (d *confusables) asciiSafe(str string, preferredScripts ScriptSet) bool {
+	a := &d.ascii[str[0]]
+	if len(str) > 1 && a.next[str[1]/64]&(1<<(str[1]%64)) != 0 {
+		return false
+	}
+	return a.homoglyphs == nil || preferredScripts.Has(a.script) ||
+		(preferredScripts.Len() > 0 && a.scripts.Intersect(preferredScripts).Len() == 0)
+}
+
+// homoglyphs returns the homoglyphs refs of key, copied from the ASCII
+// table if key is an ASCII character so that callers may modify them.
+func (d *confusables) homoglyphs(key string, refs []homoglyphRef) []Homoglyph {
+	if len(key) == 1 && key[0] < utf8.RuneSelf && d.ascii[key[0]].homoglyphs != nil {
+		return append([]Homoglyph(nil), d.ascii[key[0]].homoglyphs...)
+	}
+	return d.index.materialize(refs)
+}
+
+// asciiScripts returns the scripts of the ASCII characters str starts with,
+// along with their length in bytes.
+func (s *scriptData) asciiScripts(str string) (ScriptSet, int) {
+	var scripts ScriptSet
+	i := 0
+	for ; i < len(str) && str[i] < utf8.RuneSelf; i++ {
+		scripts.Add(s.scriptOf(rune(str[i])))
+	}
+	return scripts, i
+}
+
+// scriptsAugmentedSet returns the intersection of the augmented script sets
+// of characters from scripts without script extensions, as per
+// augmentedSet. It returns true if all of them are ignored.
+func scriptsAugmentedSet(scripts ScriptSet, skippedScripts ScriptSet, universalScripts ScriptSet) (ScriptSet, bool) {
+	var set ScriptSet
+	all := true
+	for w, word := range scripts {
+		word &^= skippedScripts[w] | universalScripts[w]
+		for ; word != 0; word &= word - 1 {
+			augmented := augmentedSets[w*64+bits.TrailingZeros64(word)]
+			if all {
+				set, all = augmented, false
+			} else {
+				set = set.Intersect(augmented)
+			}
+		}
+	}
+	return set, all
+}
@@ -0,0 +1,235 @@
+package confusablehomoglyphs
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestASCIISafe(t *testing.T) {
+	d := loadedConfusables()
+	s := defaultScripts()
+	preferred := []ScriptSet{
+		{},
+		NewScriptSet(ScriptLatin),
+		NewScriptSet(ScriptGreek),
+		NewScriptSet(ScriptLatin, ScriptCyrillic),
+	}
+
+	// safe characters are neither confusable nor the start of a sequence
+	for _, p := range preferred {
+		for c1 := 0; c1 < utf8.RuneSelf; c1++ {
+			for c2 := 0; c2 < utf8.RuneSelf; c2++ {
+				str := string([]byte{byte(c1), byte(c2)})
+				if !d.asciiSafe(str, p) {
+					continue
+				}
+				if sequence, ok := d.matchSequence(str); ok {
+					t.Errorf("unexpected safe sequence, string: %q, sequence: %q, preferred: %v\n", str, sequence, p.Scripts())
+				}
+				if _, confusable := d.result(s, str[:1], p); confusable {
+					t.Errorf("unexpected safe confusable, character: %q, preferred: %v\n", str[:1], p.Scripts())
+				}
+			}
+		}
+	}
+}
+
+func TestASCIIResolve(t *testing.T) {
+	s := defaultScripts()
+	strs := []string{"", "abc", "a-b", "1.2", "ab東京", "カタカナ-ab", "ρa", "áb"}
+	skipped := []ScriptSet{{}, universalScripts, NewScriptSet(ScriptLatin)}
+
+	// resolving ASCII stretches at once is resolving them character by
+	// character
+	for _, str := range strs {
+		for _, sk := range skipped {
+			for _, universal := range []ScriptSet{{}, universalScripts} {
+				expected, expectedAll := resolveByCharacter(s, str, sk, universal)
+				actual, all := s.resolve(str, sk, universal)
+				if actual != expected || all != expectedAll {
+					t.Errorf("unexpected resolved scripts, string: %q, expected: %v %v, actual: %v %v\n", str, expected.Scripts(), expectedAll, actual.Scripts(), all)
+				}
+			}
+		}
+	}
+}
+
+func TestASCIIWithDataset(t *testing.T) {
+	// the ASCII data follows the scripts data of the Checker
+	for _, v := range Versions() {
+		d, err := LoadDataset(v)
+		if err != nil {
+			t.Fatalf("unexpected error, expected: %v, actual: %v\n", nil, err)
+		}
+		checker := NewChecker(WithDataset(d), WithOverlay(Overlay{Remove: []string{"1"}}))
+		if checker.data.asciiScripts != checker.scripts {
+			t.Errorf("unexpected ASCII scripts data, version: %v\n", v)
+		}
+		if checker.data.ascii['1'].homoglyphs != nil {
+			t.Errorf("unexpected ASCII homoglyphs, expected: %v, actual: %v\n", nil, checker.data.ascii['1'].homoglyphs)
+		}
+	}
+}
+
+func TestASCIIHomoglyphsCopied(t *testing.T) {
+	d := loadedConfusables().withASCII(defaultScripts())
+	expected := append([]Homoglyph(nil), d.ascii['a'].homoglyphs...)
+
+	// modifying the results does not modify the ASCII data
+	results := IsConfusable("a", true, nil)
+	if len(results) != 1 {
+		t.Fatalf("unexpected results, expected: %v, actual: %v\n", 1, len(results))
+	}
+	for i := range results[0].Homoglyphs {
+		results[0].Homoglyphs[i] = Homoglyph{}
+	}
+	if !reflect.DeepEqual(d.ascii['a'].homoglyphs, expected) {
+		t.Errorf("unexpected ASCII homoglyphs, expected: %v, actual: %v\n", expected, d.ascii['a'].homoglyphs)
+	}
+	if results := IsConfusable("a", true, nil); !reflect.DeepEqual(results[0].Homoglyphs, expected) {
+		t.Errorf("unexpected homoglyphs, expected: %v, actual: %v\n", expected, results[0].Homoglyphs)
+	}
+}
+
+func TestASCIIResolveAll(t *testing.T) {
+	s := defaultScripts()
+	for _, str := range asciiStrings() {
+		for _, universal := range []ScriptSet{{}, universalScripts} {
+			expected, expectedAll := resolveByCharacter(s, str, ScriptSet{}, universal)
+			actual, all := s.resolve(str, ScriptSet{}, universal)
+			if actual != expected || all != expectedAll {
+				t.Errorf("unexpected resolved scripts, string: %q, expected: %v %v, actual: %v %v\n", str, expected.Scripts(), expectedAll, actual.Scripts(), all)
+			}
+		}
+	}
+}
+
+func TestASCIIFastPath(t *testing.T) {
+	s := defaultScripts()
+	fast := loadedConfusables().withASCII(s)
+	general := withoutASCII(fast)
+	preferred := []ScriptSet{
+		{},
+		NewScriptSet(ScriptLatin),
+		NewScriptSet(ScriptGreek),
+		NewScriptSet(ScriptCommon, ScriptCyrillic),
+	}
+	modes := []struct {
+		greedy      bool
+		occurrences bool
+	}{
+		{false, false},
+		{true, false},
+		{true, true},
+	}
+
+	// the fast path finds what looking ASCII characters up like the others
+	// finds
+	for _, str := range asciiStrings() {
+		for _, p := range preferred {
+			for _, m := range modes {
+				expected := general.find(s, str, m.greedy, m.occurrences, p)
+				actual := fast.find(s, str, m.greedy, m.occurrences, p)
+				if !reflect.DeepEqual(actual, expected) {
+					t.Errorf("unexpected confusables, string: %q, preferred: %v, greedy: %v, expected: %v, actual: %v\n", str, p.Scripts(), m.greedy, expected, actual)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkASCIIFastPath(b *testing.B) {
+	s := defaultScripts()
+	fast := loadedConfusables().withASCII(s)
+	general := withoutASCII(fast)
+	latin := NewScriptSet(ScriptLatin)
+	strs := append(append([]string{}, benchmarkCorpora[0].strs...), benchmarkCorpora[1].strs...)
+
+	b.Run("Find", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, str := range strs {
+				fast.find(s, str, true, false, latin)
+			}
+		}
+	})
+	b.Run("FindGeneral", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, str := range strs {
+				general.find(s, str, true, false, latin)
+			}
+		}
+	})
+	b.Run("Resolve", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, str := range strs {
+				s.resolve(str, ScriptSet{}, universalScripts)
+			}
+		}
+	})
+	b.Run("ResolveGeneral", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, str := range strs {
+				resolveByCharacter(s, str, ScriptSet{}, universalScripts)
+			}
+		}
+	})
+}
+
+// withoutASCII returns d without its ASCII data, so that ASCII characters
+// are looked up like the others.
+func withoutASCII(d *confusables) *confusables {
+	data := *d
+	data.ascii = [utf8.RuneSelf]asciiConfusable{}
+	data.asciiScripts = nil
+	return &data
+}
+
+// resolveByCharacter is resolve without the ASCII fast path.
+func resolveByCharacter(s *scriptData, str string, skipped ScriptSet, universal ScriptSet) (ScriptSet, bool) {
+	var resolved ScriptSet
+	all := true
+	for _, chr := range str {
+		set, universalChr := s.augmentedSet(chr, skipped, universal)
+		if universalChr {
+			continue
+		}
+		if all {
+			resolved, all = set, false
+		} else {
+			resolved = resolved.Intersect(set)
+		}
+	}
+	return resolved, all
+}
+
+// asciiStrings returns every ASCII string of one or two characters, along
+// with the ASCII confusable sequences alone and surrounded by characters.
+func asciiStrings() []string {
+	strs := []string{}
+	for c1 := 0; c1 < utf8.RuneSelf; c1++ {
+		strs = append(strs, string([]byte{byte(c1)}))
+		for c2 := 0; c2 < utf8.RuneSelf; c2++ {
+			strs = append(strs, string([]byte{byte(c1), byte(c2)}))
+		}
+	}
+	for _, k := range loadedConfusables().index.keys {
+		if sequence := k.key; isASCII(sequence) {
+			strs = append(strs, sequence, "a"+sequence+"l", "r"+sequence+sequence)
+		}
+	}
+	return strs
+}
+
+func isASCII(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if str[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
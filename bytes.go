@@ -0,0 +1,189 @@
+package confusablehomoglyphs
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// InvalidUTF8Error is returned by the byte and rune slice variants of the
+// detection functions for input which is not valid UTF-8, instead of
+// checking the invalid bytes as U+FFFD like the string functions do.
+type InvalidUTF8Error struct {
+	// Offset is the position of the first invalid byte of a byte slice, or
+	// of the first invalid rune of a rune slice.
+	Offset int
+}
+
+func (e *InvalidUTF8Error) Error() string {
+	return fmt.Sprintf("confusablehomoglyphs: invalid UTF-8 at offset %d", e.Offset)
+}
+
+// IsMixedScriptBytes is IsMixedScript for a byte slice.
+func IsMixedScriptBytes(b []byte, allowedAliases []string) (bool, error) {
+	if err := validBytes(b); err != nil {
+		return false, err
+	}
+	return IsMixedScript(bytesString(b), allowedAliases), nil
+}
+
+// IsMixedScriptRunes is IsMixedScript for a rune slice.
+func IsMixedScriptRunes(r []rune, allowedAliases []string) (bool, error) {
+	str, err := runesString(r)
+	if err != nil {
+		return false, err
+	}
+	return IsMixedScript(str, allowedAliases), nil
+}
+
+// IsConfusableBytes is IsConfusable for a byte slice. The results do not
+// reference b.
+func IsConfusableBytes(b []byte, greedy bool, preferredAliases []string) ([]ConfusableResult, error) {
+	return NewChecker(WithPreferredAliases(preferredAliases...), WithGreedy(greedy)).IsConfusableBytes(b)
+}
+
+// IsConfusableRunes is IsConfusable for a rune slice. Offset and Length of
+// the results are in bytes of the UTF-8 encoding of r, Index is the
+// position in r.
+func IsConfusableRunes(r []rune, greedy bool, preferredAliases []string) ([]ConfusableResult, error) {
+	return NewChecker(WithPreferredAliases(preferredAliases...), WithGreedy(greedy)).IsConfusableRunes(r)
+}
+
+// IsDangerousBytes is IsDangerous for a byte slice.
+func IsDangerousBytes(b []byte, preferredAliases []string) (bool, error) {
+	return NewChecker(WithPreferredAliases(preferredAliases...)).IsDangerousBytes(b)
+}
+
+// IsDangerousRunes is IsDangerous for a rune slice.
+func IsDangerousRunes(r []rune, preferredAliases []string) (bool, error) {
+	return NewChecker(WithPreferredAliases(preferredAliases...)).IsDangerousRunes(r)
+}
+
+// UniqueAliasesBytes is UniqueAliases for a byte slice.
+func UniqueAliasesBytes(b []byte) ([]string, error) {
+	if err := validBytes(b); err != nil {
+		return nil, err
+	}
+	return UniqueAliases(bytesString(b)), nil
+}
+
+// UniqueAliasesRunes is UniqueAliases for a rune slice.
+func UniqueAliasesRunes(r []rune) ([]string, error) {
+	if err := validRunes(r); err != nil {
+		return nil, err
+	}
+	s := defaultScripts()
+	var set ScriptSet
+	for _, chr := range r {
+		set.Add(s.scriptOf(chr))
+	}
+	return set.AppendAliases(make([]string, 0, set.Len())), nil
+}
+
+// IsMixedScriptBytes is IsMixedScript for a byte slice.
+func (c *Checker) IsMixedScriptBytes(b []byte) (bool, error) {
+	if err := validBytes(b); err != nil {
+		return false, err
+	}
+	return c.IsMixedScript(bytesString(b)), nil
+}
+
+// IsMixedScriptRunes is IsMixedScript for a rune slice.
+func (c *Checker) IsMixedScriptRunes(r []rune) (bool, error) {
+	str, err := runesString(r)
+	if err != nil {
+		return false, err
+	}
+	return c.IsMixedScript(str), nil
+}
+
+// IsConfusableBytes is IsConfusable for a byte slice. The results do not
+// reference b.
+func (c *Checker) IsConfusableBytes(b []byte) ([]ConfusableResult, error) {
+	if err := validBytes(b); err != nil {
+		return nil, err
+	}
+	results := c.IsConfusable(bytesString(b))
+	for i := range results {
+		results[i].Sequence = cloneString(results[i].Sequence)
+	}
+	return results, nil
+}
+
+// IsConfusableRunes is IsConfusable for a rune slice. Offset and Length of
+// the results are in bytes of the UTF-8 encoding of r, Index is the
+// position in r.
+func (c *Checker) IsConfusableRunes(r []rune) ([]ConfusableResult, error) {
+	str, err := runesString(r)
+	if err != nil {
+		return nil, err
+	}
+	return c.IsConfusable(str), nil
+}
+
+// IsDangerousBytes is IsDangerous for a byte slice.
+func (c *Checker) IsDangerousBytes(b []byte) (bool, error) {
+	if err := validBytes(b); err != nil {
+		return false, err
+	}
+	return c.IsDangerous(bytesString(b)), nil
+}
+
+// IsDangerousRunes is IsDangerous for a rune slice.
+func (c *Checker) IsDangerousRunes(r []rune) (bool, error) {
+	str, err := runesString(r)
+	if err != nil {
+		return false, err
+	}
+	return c.IsDangerous(str), nil
+}
+
+// validBytes checks that b is valid UTF-8.
+func validBytes(b []byte) error {
+	if utf8.Valid(b) {
+		return nil
+	}
+	for i := 0; i < len(b); {
+		chr, size := utf8.DecodeRune(b[i:])
+		if chr == utf8.RuneError && size == 1 {
+			return &InvalidUTF8Error{Offset: i}
+		}
+		i += size
+	}
+	return nil
+}
+
+// validRunes checks that r only holds characters which can be encoded in
+// UTF-8, i.e. no surrogate halves or values out of the Unicode range.
+func validRunes(r []rune) error {
+	for i, chr := range r {
+		if !utf8.ValidRune(chr) {
+			return &InvalidUTF8Error{Offset: i}
+		}
+	}
+	return nil
+}
+
+// runesString checks r and returns its UTF-8 encoding.
+func runesString(r []rune) (string, error) {
+	if err := validRunes(r); err != nil {
+		return "", err
+	}
+	return string(r), nil
+}
+
+// bytesString returns the bytes of b as a string without copying them.
+// The string must not outlive the call it is passed to, since b may be
+// modified afterwards: results referencing it are copied with cloneString.
+func bytesString(b []byte) string {
+	return *(*string)(unsafe.Pointer(&b))
+}
+
+// cloneString returns a copy of str which does not share its memory.
+func cloneString(str string) string {
+	var b strings.Builder
+	b.Grow(len(str))
+	b.WriteString(str)
+	return b.String()
+}
@@ -0,0 +1,141 @@
+package confusablehomoglyphs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBytesVariants(t *testing.T) {
+	cases := []string{"", "AlaskaJazz", "ΑlaskaJazz", "paρaαa", "pаypаl", "Tokyo東京", "microsoft"}
+	checker := NewChecker(WithPreferredAliases("latin"), WithGreedy(true))
+
+	for _, str := range cases {
+		b, r := []byte(str), []rune(str)
+
+		isMixedScript := IsMixedScript(str, nil)
+		if actual, err := IsMixedScriptBytes(b, nil); err != nil || actual != isMixedScript {
+			t.Errorf("unexpected isMixedScript of bytes, string: %v, expected: %v, actual: %v, %v\n", str, isMixedScript, actual, err)
+		}
+		if actual, err := IsMixedScriptRunes(r, nil); err != nil || actual != isMixedScript {
+			t.Errorf("unexpected isMixedScript of runes, string: %v, expected: %v, actual: %v, %v\n", str, isMixedScript, actual, err)
+		}
+
+		confusables := IsConfusable(str, true, []string{"latin"})
+		if actual, err := IsConfusableBytes(b, true, []string{"latin"}); err != nil || !reflect.DeepEqual(actual, confusables) {
+			t.Errorf("unexpected confusables of bytes, string: %v, expected: %v, actual: %v, %v\n", str, confusables, actual, err)
+		}
+		if actual, err := IsConfusableRunes(r, true, []string{"latin"}); err != nil || !reflect.DeepEqual(actual, confusables) {
+			t.Errorf("unexpected confusables of runes, string: %v, expected: %v, actual: %v, %v\n", str, confusables, actual, err)
+		}
+		if actual, err := checker.IsConfusableBytes(b); err != nil || !reflect.DeepEqual(actual, confusables) {
+			t.Errorf("unexpected checker confusables of bytes, string: %v, expected: %v, actual: %v, %v\n", str, confusables, actual, err)
+		}
+
+		isDangerous := IsDangerous(str, nil)
+		if actual, err := IsDangerousBytes(b, nil); err != nil || actual != isDangerous {
+			t.Errorf("unexpected isDangerous of bytes, string: %v, expected: %v, actual: %v, %v\n", str, isDangerous, actual, err)
+		}
+		if actual, err := IsDangerousRunes(r, nil); err != nil || actual != isDangerous {
+			t.Errorf("unexpected isDangerous of runes, string: %v, expected: %v, actual: %v, %v\n", str, isDangerous, actual, err)
+		}
+
+		aliases := UniqueAliases(str)
+		if actual, err := UniqueAliasesBytes(b); err != nil || !reflect.DeepEqual(actual, aliases) {
+			t.Errorf("unexpected unique aliases of bytes, string: %v, expected: %v, actual: %v, %v\n", str, aliases, actual, err)
+		}
+		if actual, err := UniqueAliasesRunes(r); err != nil || !reflect.DeepEqual(actual, aliases) {
+			t.Errorf("unexpected unique aliases of runes, string: %v, expected: %v, actual: %v, %v\n", str, aliases, actual, err)
+		}
+	}
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	checker := NewChecker()
+	byteCases := []struct {
+		b      []byte
+		offset int
+	}{
+		{[]byte("ab\xffc"), 2},
+		{[]byte("\xc3"), 0},
+		{[]byte("ρ\xed\xa0\x80"), 2},
+		{[]byte("abc\xe6\x9d"), 3},
+	}
+	for _, c := range byteCases {
+		errs := []error{}
+		_, err := IsMixedScriptBytes(c.b, nil)
+		errs = append(errs, err)
+		_, err = IsConfusableBytes(c.b, false, nil)
+		errs = append(errs, err)
+		_, err = IsDangerousBytes(c.b, nil)
+		errs = append(errs, err)
+		_, err = UniqueAliasesBytes(c.b)
+		errs = append(errs, err)
+		_, err = checker.IsMixedScriptBytes(c.b)
+		errs = append(errs, err)
+		for _, err := range errs {
+			if e, ok := err.(*InvalidUTF8Error); !ok || e.Offset != c.offset {
+				t.Errorf("unexpected error, bytes: %q, expected offset: %v, actual: %v\n", c.b, c.offset, err)
+			}
+		}
+	}
+
+	runeCases := []struct {
+		r      []rune
+		offset int
+	}{
+		{[]rune{'a', 0xD800}, 1},
+		{[]rune{-1}, 0},
+		{[]rune{'ρ', 'a', 0x110000}, 2},
+	}
+	for _, c := range runeCases {
+		errs := []error{}
+		_, err := IsMixedScriptRunes(c.r, nil)
+		errs = append(errs, err)
+		_, err = IsConfusableRunes(c.r, false, nil)
+		errs = append(errs, err)
+		_, err = IsDangerousRunes(c.r, nil)
+		errs = append(errs, err)
+		_, err = UniqueAliasesRunes(c.r)
+		errs = append(errs, err)
+		_, err = checker.IsDangerousRunes(c.r)
+		errs = append(errs, err)
+		for _, err := range errs {
+			if e, ok := err.(*InvalidUTF8Error); !ok || e.Offset != c.offset {
+				t.Errorf("unexpected error, runes: %v, expected offset: %v, actual: %v\n", c.r, c.offset, err)
+			}
+		}
+	}
+}
+
+func TestIsConfusableBytesCopiesSequences(t *testing.T) {
+	b := []byte("pаypal")
+	results, err := NewChecker(WithPreferredScripts(ScriptLatin)).IsConfusableBytes(b)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("unexpected confusables, expected: %v, actual: %v, %v\n", 1, results, err)
+	}
+	for i := range b {
+		b[i] = 'x'
+	}
+	if results[0].Sequence != "а" {
+		t.Errorf("unexpected sequence, expected: %q, actual: %q\n", "а", results[0].Sequence)
+	}
+}
+
+func TestBytesAllocations(t *testing.T) {
+	checker := NewChecker(WithPreferredScripts(ScriptLatin))
+	b := []byte("john.doe-1984_admin@example.com")
+	cases := []struct {
+		name string
+		f    func()
+	}{
+		{"IsMixedScriptBytes", func() { IsMixedScriptBytes(b, nil) }},
+		{"Checker.IsMixedScriptBytes", func() { checker.IsMixedScriptBytes(b) }},
+		{"Checker.IsDangerousBytes", func() { checker.IsDangerousBytes(b) }},
+	}
+
+	for _, c := range cases {
+		if allocs := testing.AllocsPerRun(100, c.f); allocs != 0 {
+			t.Errorf("unexpected allocations, case: %v, expected: %v, actual: %v\n", c.name, 0, allocs)
+		}
+	}
+}
@@ -1,51 +1,189 @@
 package confusablehomoglyphs
 
-//go:generate go run tools/gen.go
+import (
+	"fmt"
+	"unicode/utf8"
+)
 
-func AliasesCategories(chr rune) (string, string) {
-	var l, r, c, m int
-	l = 0
-	r = len(categoryData.CodePointsRanges) - 1
-	c = int(chr)
-
-	// binary search
-	for r >= l {
-		m = (l + r) / 2
-		if c < categoryData.CodePointsRanges[m][0] {
-			r = m - 1
-		} else if c > categoryData.CodePointsRanges[m][1] {
-			l = m + 1
-		} else {
-			return categoryData.ISO15924aliases[categoryData.CodePointsRanges[m][2]],
-				categoryData.Categories[categoryData.CodePointsRanges[m][3]]
+//go:generate go run ./tools/gen
+
+// GeneralCategory is a Unicode general category, see the Category constants.
+type GeneralCategory uint8
+
+func (c GeneralCategory) String() string {
+	if int(c) < len(categoryNames) {
+		return categoryNames[c]
+	}
+	return categoryNames[CategoryUnknown]
+}
+
+// categoryLongNames are the long names of the general categories and of
+// their major classes, from PropertyValueAliases.txt.
+var categoryLongNames = map[string]string{
+	"Lu": "Uppercase_Letter",
+	"Ll": "Lowercase_Letter",
+	"Lt": "Titlecase_Letter",
+	"Lm": "Modifier_Letter",
+	"Lo": "Other_Letter",
+	"Mn": "Nonspacing_Mark",
+	"Mc": "Spacing_Mark",
+	"Me": "Enclosing_Mark",
+	"Nd": "Decimal_Number",
+	"Nl": "Letter_Number",
+	"No": "Other_Number",
+	"Pc": "Connector_Punctuation",
+	"Pd": "Dash_Punctuation",
+	"Ps": "Open_Punctuation",
+	"Pe": "Close_Punctuation",
+	"Pi": "Initial_Punctuation",
+	"Pf": "Final_Punctuation",
+	"Po": "Other_Punctuation",
+	"Sm": "Math_Symbol",
+	"Sc": "Currency_Symbol",
+	"Sk": "Modifier_Symbol",
+	"So": "Other_Symbol",
+	"Zs": "Space_Separator",
+	"Zl": "Line_Separator",
+	"Zp": "Paragraph_Separator",
+	"Cc": "Control",
+	"Cf": "Format",
+	"Cs": "Surrogate",
+	"Co": "Private_Use",
+	"Cn": "Unassigned",
+	"L":  "Letter",
+	"M":  "Mark",
+	"N":  "Number",
+	"P":  "Punctuation",
+	"S":  "Symbol",
+	"Z":  "Separator",
+	"C":  "Other",
+}
+
+// LongName returns the long name of c, e.g. "Dash_Punctuation" for
+// CategoryPd, or "Unknown" for CategoryUnknown.
+func (c GeneralCategory) LongName() string {
+	if n, ok := categoryLongNames[c.String()]; ok && c != CategoryUnknown {
+		return n
+	}
+	return "Unknown"
+}
+
+// Major returns the major class of c, e.g. CategoryL for CategoryLu.
+// Major classes are their own major class.
+func (c GeneralCategory) Major() GeneralCategory {
+	if c == CategoryUnknown {
+		return CategoryUnknown
+	}
+	major, err := ParseGeneralCategory(c.String()[:1])
+	if err != nil {
+		return CategoryUnknown
+	}
+	return major
+}
+
+// In checks if c is class or belongs to the major class class, e.g.
+// CategoryLu is in CategoryL.
+func (c GeneralCategory) In(class GeneralCategory) bool {
+	return c != CategoryUnknown && (c == class || c.Major() == class)
+}
+
+// Collapsed returns c with the cased letters Lu, Ll and Lt collapsed into
+// CategoryL, as the category data did before distinguishing them.
+func (c GeneralCategory) Collapsed() GeneralCategory {
+	switch c {
+	case CategoryLu, CategoryLl, CategoryLt:
+		return CategoryL
+	}
+	return c
+}
+
+// ParseGeneralCategory returns the general category with the short name
+// name, e.g. "Pd", or the long name, e.g. "Dash_Punctuation".
+func ParseGeneralCategory(name string) (GeneralCategory, error) {
+	for c, n := range categoryNames {
+		if GeneralCategory(c) != CategoryUnknown && (n == name || categoryLongNames[n] == name) {
+			return GeneralCategory(c), nil
 		}
 	}
+	return CategoryUnknown, fmt.Errorf("confusablehomoglyphs: unknown general category %q", name)
+}
 
-	return "Unknown", "Zzzz"
+// ScriptCategory returns the script and the general category of chr.
+func ScriptCategory(chr rune) (Script, GeneralCategory) {
+	return defaultScripts().scriptCategory(chr)
 }
 
-func Alias(chr rune) string {
-	a, _ := AliasesCategories(chr)
-	return a
+func (s *scriptData) scriptCategory(chr rune) (Script, GeneralCategory) {
+	p := s.properties(chr)
+	return p.script, p.category
 }
 
-func Category(chr rune) string {
-	_, c := AliasesCategories(chr)
+// properties returns the properties of chr from the lookup table, in
+// constant time.
+func (s *scriptData) properties(chr rune) codePointProperties {
+	if chr < 0 || chr > utf8.MaxRune {
+		return codePointProperties{ScriptUnknown, CategoryUnknown, -1}
+	}
+	block := int(s.trie.blocks[chr>>trieBlockBits]) << trieBlockBits
+	return s.trie.properties[s.trie.values[block|int(chr)&(1<<trieBlockBits-1)]]
+}
+
+func (s *scriptData) scriptOf(chr rune) Script {
+	script, _ := s.scriptCategory(chr)
+	return script
+}
+
+// ScriptOf returns the script of chr.
+func ScriptOf(chr rune) Script {
+	s, _ := ScriptCategory(chr)
+	return s
+}
+
+// CategoryOf returns the general category of chr.
+func CategoryOf(chr rune) GeneralCategory {
+	_, c := ScriptCategory(chr)
 	return c
 }
 
-func UniqueAliases(str string) []string {
-	aSet := map[string]struct{}{}
+// UniqueScripts returns the scripts of the characters of str.
+func UniqueScripts(str string) []Script {
+	return UniqueScriptSet(str).Scripts()
+}
+
+// UniqueScriptSet returns the set of the scripts of the characters of str,
+// without allocating.
+func UniqueScriptSet(str string) ScriptSet {
+	s := defaultScripts()
+	var set ScriptSet
 	for _, chr := range str {
-		aSet[Alias(chr)] = struct{}{}
+		set.Add(s.scriptOf(chr))
 	}
+	return set
+}
 
-	keys := make([]string, len(aSet))
-	i := 0
-	for k := range aSet {
-		keys[i] = k
-		i++
-	}
+func AliasesCategories(chr rune) (string, string) {
+	s, c := ScriptCategory(chr)
+	return s.String(), c.String()
+}
+
+func Alias(chr rune) string {
+	return ScriptOf(chr).String()
+}
 
-	return keys
+func Category(chr rune) string {
+	return CategoryOf(chr).String()
+}
+
+// CollapsedCategory is Category with the cased letters collapsed into "L",
+// for compatibility with the values returned before Lu, Ll and Lt were
+// distinguished.
+func CollapsedCategory(chr rune) string {
+	return CategoryOf(chr).Collapsed().String()
+}
+
+// UniqueAliases returns the aliases of the scripts of the characters of
+// str. Use UniqueScriptSet(str).AppendAliases to reuse a slice.
+func UniqueAliases(str string) []string {
+	set := UniqueScriptSet(str)
+	return set.AppendAliases(make([]string, 0, set.Len()))
 }
@@ -12,8 +12,8 @@ func TestAliasesCategories(t *testing.T) {
 		alias    string
 		category string
 	}{
-		{'A', "LATIN", "L"},
-		{'τ', "GREEK", "L"},
+		{'A', "LATIN", "Lu"},
+		{'τ', "GREEK", "Ll"},
 		{'-', "COMMON", "Pd"},
 	}
 
@@ -29,6 +29,111 @@ func TestAliasesCategories(t *testing.T) {
 	}
 }
 
+func TestScriptCategory(t *testing.T) {
+	cases := []struct {
+		char     rune
+		script   Script
+		category GeneralCategory
+	}{
+		{'A', ScriptLatin, CategoryLu},
+		{'τ', ScriptGreek, CategoryLl},
+		{'-', ScriptCommon, CategoryPd},
+		{'\U0010FFFF', ScriptUnknown, CategoryUnknown},
+	}
+
+	for _, c := range cases {
+		script, category := ScriptCategory(c.char)
+		if script != c.script || category != c.category {
+			t.Errorf("unexpected script and category, expected: %v %v, actual: %v %v\n", c.script, c.category, script, category)
+		}
+	}
+}
+
+func TestParseGeneralCategory(t *testing.T) {
+	cases := []struct {
+		name     string
+		category GeneralCategory
+		err      bool
+	}{
+		{"Pd", CategoryPd, false},
+		{"L", CategoryL, false},
+		{"Uppercase_Letter", CategoryLu, false},
+		{"Letter", CategoryL, false},
+		{"pd", CategoryUnknown, true},
+		{"Zzzz", CategoryUnknown, true},
+	}
+
+	for _, c := range cases {
+		category, err := ParseGeneralCategory(c.name)
+		if category != c.category || (err != nil) != c.err {
+			t.Errorf("unexpected general category, name: %v, expected: %v %v, actual: %v %v\n", c.name, c.category, c.err, category, err)
+		}
+	}
+}
+
+func TestGeneralCategoryLongName(t *testing.T) {
+	cases := []struct {
+		category GeneralCategory
+		name     string
+	}{
+		{CategoryLu, "Uppercase_Letter"},
+		{CategoryPd, "Dash_Punctuation"},
+		{CategoryL, "Letter"},
+		{CategoryUnknown, "Unknown"},
+	}
+
+	for _, c := range cases {
+		name := c.category.LongName()
+		if name != c.name {
+			t.Errorf("unexpected long name, expected: %v, actual: %v\n", c.name, name)
+		}
+	}
+}
+
+func TestGeneralCategoryMajor(t *testing.T) {
+	cases := []struct {
+		category GeneralCategory
+		major    GeneralCategory
+	}{
+		{CategoryLu, CategoryL},
+		{CategoryLt, CategoryL},
+		{CategoryMn, CategoryM},
+		{CategoryPd, CategoryP},
+		{CategoryZs, CategoryZ},
+		{CategoryCc, CategoryC},
+		{CategoryS, CategoryS},
+		{CategoryUnknown, CategoryUnknown},
+	}
+
+	for _, c := range cases {
+		major := c.category.Major()
+		if major != c.major {
+			t.Errorf("unexpected major class, category: %v, expected: %v, actual: %v\n", c.category, c.major, major)
+		}
+	}
+}
+
+func TestGeneralCategoryIn(t *testing.T) {
+	cases := []struct {
+		category GeneralCategory
+		class    GeneralCategory
+		in       bool
+	}{
+		{CategoryLl, CategoryL, true},
+		{CategoryLl, CategoryLl, true},
+		{CategoryLl, CategoryLu, false},
+		{CategoryNd, CategoryL, false},
+		{CategoryUnknown, CategoryUnknown, false},
+	}
+
+	for _, c := range cases {
+		in := c.category.In(c.class)
+		if in != c.in {
+			t.Errorf("unexpected in, category: %v, class: %v, expected: %v, actual: %v\n", c.category, c.class, c.in, in)
+		}
+	}
+}
+
 func TestAlias(t *testing.T) {
 	cases := []struct {
 		char  rune
@@ -52,8 +157,8 @@ func TestCategory(t *testing.T) {
 		char     rune
 		category string
 	}{
-		{'A', "L"},
-		{'τ', "L"},
+		{'A', "Lu"},
+		{'τ', "Ll"},
 		{'-', "Pd"},
 	}
 
@@ -65,6 +170,26 @@ func TestCategory(t *testing.T) {
 	}
 }
 
+func TestCollapsedCategory(t *testing.T) {
+	cases := []struct {
+		char     rune
+		category string
+	}{
+		{'A', "L"},
+		{'τ', "L"},
+		{'ǅ', "L"},
+		{'ʰ', "Lm"},
+		{'-', "Pd"},
+	}
+
+	for _, c := range cases {
+		category := CollapsedCategory(c.char)
+		if category != c.category {
+			t.Errorf("unexpected collapsed category, expected: %v, actual: %v\n", c.category, category)
+		}
+	}
+}
+
 func TestUniqueAliases(t *testing.T) {
 	cases := []struct {
 		str     string
@@ -82,6 +207,31 @@ func TestUniqueAliases(t *testing.T) {
 	}
 }
 
+func TestCategoriesAllocations(t *testing.T) {
+	testCorpusAllocations(t, "AliasesCategories", func(str string) {
+		for _, chr := range str {
+			AliasesCategories(chr)
+		}
+	}, map[string]float64{
+		"ASCIIUsernames": 0, "ASCIIStretches": 0, "CJKText": 0, "MixedScript": 0,
+	})
+	testCorpusAllocations(t, "UniqueAliases", func(str string) { UniqueAliases(str) }, map[string]float64{
+		"ASCIIUsernames": 16, "ASCIIStretches": 4, "CJKText": 1, "MixedScript": 16,
+	})
+}
+
+func BenchmarkAliasesCategories(b *testing.B) {
+	benchmarkCorpus(b, func(str string) {
+		for _, chr := range str {
+			AliasesCategories(chr)
+		}
+	})
+}
+
+func BenchmarkUniqueAliases(b *testing.B) {
+	benchmarkCorpus(b, func(str string) { UniqueAliases(str) })
+}
+
 func sameArray(arr1 []string, arr2 []string) bool {
 	if len(arr1) != len(arr2) {
 		return false
@@ -0,0 +1,156 @@
+package confusablehomoglyphs
+
+// Checker checks strings against a fixed configuration, built once with
+// NewChecker. It is safe for concurrent use.
+type Checker struct {
+	preferredScripts ScriptSet
+	// noPreferredScripts is set when preferred aliases were given but none
+	// of them is known: nothing is then confusable with them.
+	noPreferredScripts bool
+	allowedScripts     ScriptSet
+	greedy             bool
+	restrictionLevel   Restriction
+	scripts            *scriptData
+	data               *confusables
+	overlays           []Overlay
+}
+
+// Option configures a Checker.
+type Option func(*Checker)
+
+// WithPreferredScripts sets the scripts considered as your 'base' unicode
+// blocks when looking for confusables, see IsConfusable.
+func WithPreferredScripts(scripts ...Script) Option {
+	return func(c *Checker) {
+		c.preferredScripts = NewScriptSet(scripts...)
+		c.noPreferredScripts = false
+	}
+}
+
+// WithPreferredAliases is WithPreferredScripts for script aliases.
+// Unknown aliases are ignored, use ParseScript to detect them. If all of
+// them are unknown, no character is confusable, as no character can be
+// confused with one of the given scripts.
+func WithPreferredAliases(aliases ...string) Option {
+	return func(c *Checker) {
+		c.preferredScripts = aliasesSet(aliases)
+		c.noPreferredScripts = len(aliases) > 0 && c.preferredScripts.Len() == 0
+	}
+}
+
+// WithAllowedScripts sets the scripts excluded when checking for
+// mixed-scripts, see IsMixedScript. Common and Inherited are allowed by
+// default.
+func WithAllowedScripts(scripts ...Script) Option {
+	return func(c *Checker) {
+		c.allowedScripts = NewScriptSet(scripts...)
+	}
+}
+
+// WithAllowedAliases is WithAllowedScripts for script aliases.
+// Unknown aliases are ignored, use ParseScript to detect them.
+func WithAllowedAliases(aliases ...string) Option {
+	return func(c *Checker) {
+		c.allowedScripts = aliasesSet(aliases)
+	}
+}
+
+// WithGreedy makes the Checker report all confusable characters instead of
+// only the first one found.
+func WithGreedy(greedy bool) Option {
+	return func(c *Checker) {
+		c.greedy = greedy
+	}
+}
+
+// WithRestrictionLevel sets the least restrictive level accepted by the
+// Checker, see RestrictionLevel. Strings above it are dangerous.
+func WithRestrictionLevel(level Restriction) Option {
+	return func(c *Checker) {
+		c.restrictionLevel = level
+	}
+}
+
+// NewChecker returns a Checker configured with options.
+func NewChecker(options ...Option) *Checker {
+	return newChecker(nil, options)
+}
+
+// newChecker returns a Checker configured with options which uses source
+// as confusables data if not nil.
+func newChecker(source *confusables, options []Option) *Checker {
+	c := &Checker{
+		allowedScripts: universalScripts,
+		scripts:        defaultScripts(),
+		data:           loadedConfusables(),
+	}
+	for _, o := range options {
+		o(c)
+	}
+	if source != nil {
+		c.data = source
+	}
+	for _, o := range c.overlays {
+		c.data = c.data.withOverlay(o)
+	}
+	c.data = c.data.withASCII(c.scripts)
+	return c
+}
+
+// IsMixedScript checks if str contains mixed-scripts content,
+// excluding the allowed scripts.
+func (c *Checker) IsMixedScript(str string) bool {
+	return c.scripts.isMixedScript(str, c.allowedScripts)
+}
+
+// IsConfusable check if str contains characters which might be confusable
+// with characters from the preferred scripts.
+func (c *Checker) IsConfusable(str string) []ConfusableResult {
+	return c.find(str, c.greedy, false)
+}
+
+// FindConfusables reports every occurrence of characters which might be
+// confusable with characters from the preferred scripts.
+func (c *Checker) FindConfusables(str string) []ConfusableResult {
+	return c.find(str, true, true)
+}
+
+// Skeleton is Skeleton with the confusables data of the Checker.
+func (c *Checker) Skeleton(str string) string {
+	return c.data.skeleton(str)
+}
+
+// MapPrototypes is MapPrototypes with the confusables data of the Checker.
+func (c *Checker) MapPrototypes(str string) string {
+	return c.data.mapPrototypes(str)
+}
+
+// AreConfusable checks if the distinct strings a and b render alike with
+// the confusables data of the Checker, see AreConfusable.
+func (c *Checker) AreConfusable(a, b string) (bool, []ConfusableDifference) {
+	return c.data.areConfusable(a, b)
+}
+
+// IsRestricted checks if the restriction level of str is above the one
+// accepted by the Checker. It is always false if no level was set.
+func (c *Checker) IsRestricted(str string) bool {
+	return c.restrictionLevel != 0 && c.scripts.restrictionLevel(str) > c.restrictionLevel
+}
+
+// IsDangerous checks if str can be dangerous, i.e. is it not only
+// mixed-scripts but also contains characters from other scripts than the
+// preferred ones that might be confusable with characters from them.
+// Strings above the accepted restriction level are dangerous as well.
+func (c *Checker) IsDangerous(str string) bool {
+	if c.IsRestricted(str) {
+		return true
+	}
+	return c.IsMixedScript(str) && len(c.find(str, false, false)) > 0
+}
+
+func (c *Checker) find(str string, greedy bool, occurrences bool) []ConfusableResult {
+	if c.noPreferredScripts {
+		return []ConfusableResult{}
+	}
+	return c.data.find(c.scripts, str, greedy, occurrences, c.preferredScripts)
+}
@@ -0,0 +1,64 @@
+package confusablehomoglyphs
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestChecker(t *testing.T) {
+	cases := []struct {
+		options          []Option
+		str              string
+		isMixedScript    bool
+		confusablesCount int
+		isDangerous      bool
+	}{
+		{nil, "Alloρ", true, 1, true},
+		{nil, "ρτ.τ", false, 1, false},
+		{[]Option{WithAllowedAliases()}, "ρτ.τ", true, 1, true},
+		{[]Option{WithPreferredAliases("latin")}, "paρa", true, 1, true},
+		{[]Option{WithPreferredScripts(ScriptLatin)}, "paρa", true, 1, true},
+		{[]Option{WithPreferredAliases("latn")}, "paρa", true, 0, false},
+		{[]Option{WithPreferredAliases("latn", "latin")}, "paρa", true, 1, true},
+		{[]Option{WithAllowedScripts(ScriptCommon, ScriptGreek)}, "Alloρ", false, 1, false},
+		{[]Option{WithPreferredAliases("latin"), WithGreedy(true)}, "paρaαa", true, 2, true},
+		{[]Option{WithPreferredAliases("latin")}, "AlloΓ", true, 0, false},
+		{[]Option{WithPreferredAliases("latin"), WithRestrictionLevel(ASCIIOnly)}, "Café", false, 0, true},
+		{[]Option{WithPreferredAliases("latin"), WithRestrictionLevel(HighlyRestrictive)}, "Tokyo東京", true, 0, false},
+		{[]Option{WithPreferredAliases("latin"), WithRestrictionLevel(HighlyRestrictive)}, "Shalomשלום", true, 1, true},
+	}
+
+	for _, c := range cases {
+		checker := NewChecker(c.options...)
+		isMixedScript := checker.IsMixedScript(c.str)
+		if isMixedScript != c.isMixedScript {
+			t.Errorf("unexpected isMixedScript, string: %v, expected: %v, actual: %v\n", c.str, c.isMixedScript, isMixedScript)
+		}
+		confusablesCount := len(checker.IsConfusable(c.str))
+		if confusablesCount != c.confusablesCount {
+			t.Errorf("unexpected confusables count, string: %v, expected: %v, actual: %v\n", c.str, c.confusablesCount, confusablesCount)
+		}
+		isDangerous := checker.IsDangerous(c.str)
+		if isDangerous != c.isDangerous {
+			t.Errorf("unexpected isDangerous, string: %v, expected: %v, actual: %v\n", c.str, c.isDangerous, isDangerous)
+		}
+	}
+}
+
+func TestCheckerConcurrentUse(t *testing.T) {
+	checker := NewChecker(WithPreferredAliases("latin"), WithGreedy(true))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if !checker.IsDangerous("ΑlaskaJazz") || len(checker.IsConfusable("paρaαa")) != 2 {
+					t.Error("unexpected result from concurrent use")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
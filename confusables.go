@@ -1,40 +1,34 @@
 package confusablehomoglyphs
 
 import (
+	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 type ConfusableResult struct {
 	Character  rune        `json:"character"`
+	Sequence   string      `json:"sequence"`
 	Alias      string      `json:"alias"`
 	Homoglyphs []Homoglyph `json:"homoglyphs"`
+	// Offset and Length locate the sequence in bytes, Index is the
+	// position of its first character in runes.
+	Offset int `json:"offset"`
+	Length int `json:"length"`
+	Index  int `json:"index"`
 }
 
 // IsMixedScript checks if str contains mixed-scripts content,
 // excluding script blocks aliases in allowedAliases.
 // E.g. ``B. C`` is not considered mixed-scripts by default: it contains characters
 // from Latin and Common, but Common is excluded by default.
+// The script extensions of characters are taken into account, so that e.g.
+// Han, Hiragana and Katakana can be mixed as Japanese, see ResolvedScripts.
 func IsMixedScript(str string, allowedAliases []string) bool {
 	if allowedAliases == nil {
-		allowedAliases = []string{"COMMON"}
+		return defaultScripts().isMixedScript(str, universalScripts)
 	}
-
-	allowedAliasesSet := map[string]interface{}{}
-	for _, a := range allowedAliases {
-		allowedAliasesSet[strings.ToUpper(a)] = struct{}{}
-	}
-
-	uniqueAliases := UniqueAliases(str)
-
-	count := 0
-	for _, ua := range uniqueAliases {
-		if _, ok := allowedAliasesSet[ua]; ok {
-			continue
-		}
-
-		count++
-	}
-	return count > 1
+	return defaultScripts().isMixedScript(str, aliasesSet(allowedAliases))
 }
 
 // IsConfusable check if str contains characters which might be confusable with
@@ -44,69 +38,196 @@ func IsMixedScript(str string, allowedAliases []string) bool {
 // all of them.
 // preferredAliases can take an array of unicode block aliases to
 // be considered as your 'base' unicode blocks
+// Sequences of characters confusable as a whole, e.g. "rn" which looks
+// like "m", are matched longest first and reported with their Sequence.
 func IsConfusable(str string, greedy bool, preferredAliases []string) []ConfusableResult {
-	preferredAliasesSet := map[string]struct{}{}
-	for _, a := range preferredAliases {
-		preferredAliasesSet[strings.ToUpper(a)] = struct{}{}
-	}
+	return NewChecker(WithPreferredAliases(preferredAliases...), WithGreedy(greedy)).IsConfusable(str)
+}
+
+// FindConfusables works like IsConfusable in greedy mode, but it reports
+// every occurrence of the confusable characters rather than the first one.
+func FindConfusables(str string, preferredAliases []string) []ConfusableResult {
+	return NewChecker(WithPreferredAliases(preferredAliases...)).FindConfusables(str)
+}
 
+func (d *confusables) find(s *scriptData, str string, greedy bool, occurrences bool, preferredScripts ScriptSet) []ConfusableResult {
 	outputs := []ConfusableResult{}
-	checked := map[rune]struct{}{}
-	for _, chr := range str {
-		if _, ok := checked[chr]; ok {
-			continue
+	var checked map[string]*ConfusableResult
+	check := func(sequence string, offset int, length int, index int) bool {
+		result, ok := checked[sequence]
+		if !ok {
+			if r, confusable := d.result(s, sequence, preferredScripts); confusable {
+				result = &r
+			}
+			if checked == nil {
+				checked = map[string]*ConfusableResult{}
+			}
+			checked[sequence] = result
+		} else if !occurrences {
+			return result != nil
 		}
-		checked[chr] = struct{}{}
-		charAlias := Alias(chr)
-		if _, ok := preferredAliasesSet[charAlias]; ok {
-			// it's safe if the character might be confusable with homoglyphs from other
-			// categories than our preferred categories (=aliases)
-			continue
+		if result == nil {
+			return false
 		}
-		found, ok := confusablesData[string(chr)]
-		if !ok {
+
+		output := *result
+		output.Offset = offset
+		output.Length = length
+		output.Index = index
+		outputs = append(outputs, output)
+		return true
+	}
+
+	for i, index := 0, 0; i < len(str); {
+		if str[i] < utf8.RuneSelf && d.asciiScripts != nil && d.asciiSafe(str[i:], preferredScripts) {
+			i++
+			index++
 			continue
 		}
-		// character λ is considered confusable if λ can be confused with a character from
-		// preferred_aliases, e.g. if 'LATIN', 'ρ' is confusable with 'p' from LATIN.
-		// if 'LATIN', 'Γ' is not confusable because in all the characters confusable with Γ,
-		// none of them is LATIN.
-		var potentiallyConfusable []Homoglyph
-		if len(preferredAliasesSet) > 0 {
-			potentiallyConfusable = []Homoglyph{}
-		OUTER:
-			for _, d := range found {
-				for _, glyph := range d.C {
-					a := Alias(glyph)
-					if _, ok := preferredAliasesSet[a]; ok {
-						potentiallyConfusable = found
-						break OUTER
-					}
-				}
-			}
+		n := len(outputs)
+		if sequence, ok := d.matchSequence(str[i:]); ok && check(sequence, i, len(sequence), index) {
+			i += len(sequence)
+			index += utf8.RuneCountInString(sequence)
 		} else {
-			potentiallyConfusable = found
+			chr, size := utf8.DecodeRuneInString(str[i:])
+			sequence := str[i : i+size]
+			if chr == utf8.RuneError {
+				// invalid UTF-8 is checked as U+FFFD
+				sequence = string(chr)
+			}
+			check(sequence, i, size, index)
+			i += size
+			index++
 		}
+		if !greedy && len(outputs) > n {
+			return outputs
+		}
+	}
+
+	return outputs
+}
 
-		if len(potentiallyConfusable) > 0 {
-			outputs = append(outputs, ConfusableResult{
-				Character:  chr,
-				Alias:      charAlias,
-				Homoglyphs: potentiallyConfusable,
-			})
-			if !greedy {
-				return outputs
+func (d *confusables) result(s *scriptData, sequence string, preferredScripts ScriptSet) (ConfusableResult, bool) {
+	chr, _ := utf8.DecodeRuneInString(sequence)
+	charScript := s.scriptOf(chr)
+	if s.inScripts(sequence, preferredScripts) {
+		// it's safe if the character might be confusable with homoglyphs from other
+		// categories than our preferred categories (=aliases)
+		return ConfusableResult{}, false
+	}
+	found, ok := d.index.lookup(sequence)
+	if !ok {
+		return ConfusableResult{}, false
+	}
+	// character λ is considered confusable if λ can be confused with a character from
+	// preferred_aliases, e.g. if 'LATIN', 'ρ' is confusable with 'p' from LATIN.
+	// if 'LATIN', 'Γ' is not confusable because in all the characters confusable with Γ,
+	// none of them is LATIN.
+	potentiallyConfusable := true
+	if preferredScripts.Len() > 0 {
+		potentiallyConfusable = false
+	OUTER:
+		for _, h := range found {
+			for _, glyph := range d.index.char(h) {
+				if preferredScripts.Has(s.scriptOf(glyph)) {
+					potentiallyConfusable = true
+					break OUTER
+				}
 			}
 		}
 	}
 
-	return outputs
+	if !potentiallyConfusable {
+		return ConfusableResult{}, false
+	}
+	return ConfusableResult{
+		Character:  chr,
+		Sequence:   sequence,
+		Alias:      charScript.String(),
+		Homoglyphs: d.homoglyphs(sequence, found),
+	}, true
+}
+
+// inScripts checks if all characters of str belong to scripts.
+func (s *scriptData) inScripts(str string, scripts ScriptSet) bool {
+	for _, chr := range str {
+		if !scripts.Has(s.scriptOf(chr)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchSequence returns the longest sequence from the confusables data
+// which str starts with.
+func (d *confusables) matchSequence(str string) (string, bool) {
+	var sequences []string
+	if str[0] < utf8.RuneSelf && d.asciiScripts != nil {
+		sequences = d.ascii[str[0]].sequences
+	} else {
+		chr, _ := utf8.DecodeRuneInString(str)
+		sequences = d.sequencesOf(chr)
+	}
+	for _, s := range sequences {
+		if strings.HasPrefix(str, s) {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// sequencesOf returns the sequences of the confusables data starting with
+// chr, longest first.
+func (d *confusables) sequencesOf(chr rune) []string {
+	i := sort.Search(len(d.sequenceStarts), func(i int) bool {
+		return d.sequenceStarts[i].chr >= chr
+	})
+	if i == len(d.sequenceStarts) || d.sequenceStarts[i].chr != chr {
+		return nil
+	}
+	k := d.sequenceStarts[i]
+	return d.sequences[k.start:k.end:k.end]
+}
+
+// buildSequences sets the sequences of d from the keys of its index.
+func (d *confusables) buildSequences() {
+	d.sequences = make([]string, len(d.index.keys))
+	for i, k := range d.index.keys {
+		d.sequences[i] = k.key
+	}
+	sort.Slice(d.sequences, func(i, j int) bool {
+		return sequenceLess(d.sequences[i], d.sequences[j])
+	})
+
+	d.sequenceStarts = []runeKey{}
+	for i, s := range d.sequences {
+		chr, _ := utf8.DecodeRuneInString(s)
+		if n := len(d.sequenceStarts); n > 0 && d.sequenceStarts[n-1].chr == chr {
+			d.sequenceStarts[n-1].end++
+			continue
+		}
+		d.sequenceStarts = append(d.sequenceStarts, runeKey{chr: chr, start: uint32(i), end: uint32(i + 1)})
+	}
+}
+
+// sequenceLess orders sequences by their first character, then longest
+// first.
+func sequenceLess(a, b string) bool {
+	chrA, _ := utf8.DecodeRuneInString(a)
+	chrB, _ := utf8.DecodeRuneInString(b)
+	if chrA != chrB {
+		return chrA < chrB
+	}
+	la, lb := utf8.RuneCountInString(a), utf8.RuneCountInString(b)
+	if la != lb {
+		return la > lb
+	}
+	return a < b
 }
 
 // IsDangerous checks if str can be dangerous, i.e. is it not only mixed-scripts
 // but also contains characters from other scripts than the ones in preferredAliases
 // that might be confusable with characters from scripts in preferredAliases.
 func IsDangerous(str string, preferredAliases []string) bool {
-	confusablesResult := IsConfusable(str, false, preferredAliases)
-	return IsMixedScript(str, nil) && len(confusablesResult) > 0
+	return NewChecker(WithPreferredAliases(preferredAliases...)).IsDangerous(str)
 }
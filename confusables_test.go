@@ -1,6 +1,9 @@
 package confusablehomoglyphs
 
 import (
+	"math"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -14,6 +17,9 @@ func TestIsMixedScript(t *testing.T) {
 		{"ρτ.τ", nil, false},
 		{"ρτ.τ", []string{}, true},
 		{"Alloτ", nil, true},
+		{"ひらがなカタカナ漢字", nil, false},
+		{"cafe\u0301", nil, false},
+		{"Alloー", []string{}, true},
 	}
 
 	for _, c := range cases {
@@ -24,6 +30,26 @@ func TestIsMixedScript(t *testing.T) {
 	}
 }
 
+func TestIsMixedScriptExtensions(t *testing.T) {
+	s := withExtensions(defaultScripts(), testExtensions)
+	cases := []struct {
+		str            string
+		allowedAliases []string
+		isMixedScript  bool
+	}{
+		{"カタカナー", []string{}, false},
+		{"اُردو", []string{"common"}, false},
+		{"Alloー", []string{}, true},
+	}
+
+	for _, c := range cases {
+		isMixedScript := s.isMixedScript(c.str, aliasesSet(c.allowedAliases))
+		if isMixedScript != c.isMixedScript {
+			t.Errorf("unexpected isMixedScript, string: %v, expected: %v, actual: %v\n", c.str, c.isMixedScript, isMixedScript)
+		}
+	}
+}
+
 func TestIsConfusable(t *testing.T) {
 	cases := []struct {
 		str              string
@@ -42,6 +68,7 @@ func TestIsConfusable(t *testing.T) {
 			}
 		}},
 		{"Abç", []string{"latin"}, false, func(r []ConfusableResult) {}},
+		{"paρa", []string{"latn"}, false, func(r []ConfusableResult) {}},
 		{"AlloΓ", []string{"latin"}, false, func(r []ConfusableResult) {}},
 		{"ρττ", []string{"greek"}, false, func(r []ConfusableResult) {}},
 		{"ρτ.τ", []string{"greek", "common"}, false, func(r []ConfusableResult) {}},
@@ -53,6 +80,42 @@ func TestIsConfusable(t *testing.T) {
 				t.Errorf("unexpected confusable result: %v\n", r)
 			}
 		}},
+		{"rn", nil, true, func(r []ConfusableResult) {
+			if r[0].Character != 'r' ||
+				r[0].Sequence != "rn" ||
+				r[0].Alias != "LATIN" ||
+				r[0].Homoglyphs[1].C != "m" {
+				t.Errorf("unexpected confusable result: %v\n", r)
+			}
+		}},
+		{"rn", []string{"latin"}, false, func(r []ConfusableResult) {}},
+		{"a·b", []string{"canadian_aboriginal"}, true, func(r []ConfusableResult) {
+			if r[0].Sequence != "·b" || r[0].Homoglyphs[0].C != "ᑾ" {
+				t.Errorf("unexpected confusable result: %v\n", r)
+			}
+		}},
+		{"ו", nil, true, func(r []ConfusableResult) {
+			if r[0].Character != 'ו' ||
+				r[0].Sequence != "ו" ||
+				r[0].Alias != "HEBREW" ||
+				r[0].Homoglyphs[0].C != "l" {
+				t.Errorf("unexpected confusable result: %v\n", r)
+			}
+		}},
+		{"هو", []string{"latin"}, true, func(r []ConfusableResult) {
+			if r[0].Character != 'ه' || r[0].Alias != "ARABIC" || r[0].Homoglyphs[0].C != "o" {
+				t.Errorf("unexpected confusable result: %v\n", r)
+			}
+		}},
+		{"l", []string{"hebrew"}, true, func(r []ConfusableResult) {
+			found := false
+			for _, h := range r[0].Homoglyphs {
+				found = found || (h.C == "ו" && h.N == "HEBREW LETTER VAV")
+			}
+			if !found {
+				t.Errorf("unexpected confusable result: %v\n", r)
+			}
+		}},
 	}
 
 	for _, c := range cases {
@@ -65,6 +128,62 @@ func TestIsConfusable(t *testing.T) {
 	}
 }
 
+func TestIsConfusableGreedy(t *testing.T) {
+	cases := []struct {
+		str              string
+		preferredAliases []string
+		sequences        []string
+	}{
+		{"paρa", []string{"latin"}, []string{"ρ"}},
+		{"rnrnm", nil, []string{"rn", "m"}},
+		{"ρaρa", nil, []string{"ρ", "a"}},
+	}
+
+	for _, c := range cases {
+		confusableResult := IsConfusable(c.str, true, c.preferredAliases)
+		sequences := []string{}
+		for _, r := range confusableResult {
+			sequences = append(sequences, r.Sequence)
+		}
+		if !reflect.DeepEqual(sequences, c.sequences) {
+			t.Errorf("unexpected confusable sequences, string: %v, expected: %v, actual: %v\n", c.str, c.sequences, sequences)
+		}
+	}
+}
+
+func TestFindConfusables(t *testing.T) {
+	type position struct {
+		sequence string
+		offset   int
+		length   int
+		index    int
+	}
+	cases := []struct {
+		str              string
+		preferredAliases []string
+		positions        []position
+	}{
+		{"paρa", []string{"latin"}, []position{{"ρ", 2, 2, 2}}},
+		{"ρaρa", []string{"latin"}, []position{{"ρ", 0, 2, 0}, {"ρ", 3, 2, 2}}},
+		{"éρrnρ", nil, []position{{"ρ", 2, 2, 1}, {"rn", 4, 2, 2}, {"ρ", 6, 2, 4}}},
+		{"ab\xffρ", []string{"latin"}, []position{{"ρ", 3, 2, 3}}},
+		{"paypaו", []string{"latin"}, []position{{"ו", 5, 2, 5}}},
+		{"gههgle", []string{"latin"}, []position{{"ه", 1, 2, 1}, {"ه", 3, 2, 2}}},
+		{"Allo", []string{"latin"}, []position{}},
+	}
+
+	for _, c := range cases {
+		confusableResult := FindConfusables(c.str, c.preferredAliases)
+		positions := []position{}
+		for _, r := range confusableResult {
+			positions = append(positions, position{r.Sequence, r.Offset, r.Length, r.Index})
+		}
+		if !reflect.DeepEqual(positions, c.positions) {
+			t.Errorf("unexpected confusable positions, string: %v, expected: %v, actual: %v\n", c.str, c.positions, positions)
+		}
+	}
+}
+
 func TestDangerous(t *testing.T) {
 	cases := []struct {
 		str              string
@@ -76,6 +195,10 @@ func TestDangerous(t *testing.T) {
 		{"Alloρ", []string{}, true},
 		{"AlaskaJazz", []string{}, false},
 		{"ΑlaskaJazz", []string{}, true},
+		{"ΑlaskaJazz", []string{"latn"}, false},
+		{"paypaו", []string{"latin"}, true},
+		{"gهogle", []string{"latin"}, true},
+		{"שלום", []string{"hebrew"}, false},
 	}
 
 	for _, c := range cases {
@@ -85,3 +208,131 @@ func TestDangerous(t *testing.T) {
 		}
 	}
 }
+
+// benchmarkCorpora are realistic inputs: ASCII usernames, mostly ASCII
+// strings, long CJK text, and adversarial mixed-script strings.
+var benchmarkCorpora = []struct {
+	name string
+	strs []string
+}{
+	{"ASCIIUsernames", []string{
+		"john.doe-1984_admin@example.com", "alice", "bob_smith", "charlie99",
+		"dave.jones", "eve-2000", "mallory", "trent_admin", "peggy.sue",
+		"victor1984", "walter_white", "oscar.wilde", "rn", "paypal", "microsoft",
+		"root",
+	}},
+	{"ASCIIStretches", []string{
+		"john.doe-1984_admin@exаmple.com/ρaypal",
+		"https://www.paypal.com/signin?country.x=US&locale.x=en_US&ref=аccount",
+		"Dear customer, your аccount has been suspended, please log in at once.",
+		"café.owner@restaurant.example.com",
+	}},
+	{"CJKText", []string{strings.Repeat("東京は日本の首都であり、世界有数の大都市です。"+
+		"ひらがなとカタカナと漢字を組み合わせて書かれる日本語は、スクリプトが混在しても一つの書記体系として扱われます。"+
+		"中文的简体字和繁體字在同一个句子里出现也很常见。한국어 텍스트도 함께 나타날 수 있습니다。", 8)}},
+	{"MixedScript", []string{
+		"pаypal", "аррӏе", "gооgle", "ⅿicrosoft", "fасеbооk", "twіtter",
+		"ѕесurіtу", "bаnk-оf-аmеrіса", "vvikipedia", "rnicrosoft", "ΡΑΥΡΑΙ",
+		"paypa\u200bl", "ʘK", "аdmin東京", "ﺍﻟﻌﺮﺑﻴﺔlatin", "x\u0301\u0327y",
+	}},
+}
+
+// benchmarkCorpus runs f on every string of every corpus.
+func benchmarkCorpus(b *testing.B, f func(str string)) {
+	for _, c := range benchmarkCorpora {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, str := range c.strs {
+					f(str)
+				}
+			}
+		})
+	}
+}
+
+// The package functions build a Checker on every call, their benchmarks
+// include that cost.
+
+func BenchmarkIsConfusable(b *testing.B) {
+	benchmarkCorpus(b, func(str string) { IsConfusable(str, true, []string{"latin"}) })
+}
+
+func BenchmarkIsMixedScript(b *testing.B) {
+	benchmarkCorpus(b, func(str string) { IsMixedScript(str, nil) })
+}
+
+func BenchmarkIsDangerous(b *testing.B) {
+	benchmarkCorpus(b, func(str string) { IsDangerous(str, []string{"latin"}) })
+}
+
+func BenchmarkCheckerIsConfusable(b *testing.B) {
+	checker := NewChecker(WithPreferredScripts(ScriptLatin), WithGreedy(true))
+	benchmarkCorpus(b, func(str string) { checker.IsConfusable(str) })
+}
+
+func BenchmarkCheckerIsMixedScript(b *testing.B) {
+	checker := NewChecker()
+	benchmarkCorpus(b, func(str string) { checker.IsMixedScript(str) })
+}
+
+func BenchmarkCheckerIsDangerous(b *testing.B) {
+	checker := NewChecker(WithPreferredScripts(ScriptLatin))
+	benchmarkCorpus(b, func(str string) { checker.IsDangerous(str) })
+}
+
+// allocsHeadroom is the slack given to the non-zero allocation limits,
+// which are the counts measured when they were set: the allocations of the
+// maps built while checking strings vary with their hash seeds, and with
+// the map implementation of the Go version. Zero limits get no headroom.
+const allocsHeadroom = 1.25
+
+// testCorpusAllocations checks that running f on every string of each
+// corpus allocates at most maxAllocs[corpus] times, plus allocsHeadroom.
+func testCorpusAllocations(t *testing.T, name string, f func(str string), maxAllocs map[string]float64) {
+	for _, c := range benchmarkCorpora {
+		allocs := testing.AllocsPerRun(20, func() {
+			for _, str := range c.strs {
+				f(str)
+			}
+		})
+		limit := math.Ceil(maxAllocs[c.name] * allocsHeadroom)
+		if allocs > limit {
+			t.Errorf("unexpected allocations, case: %v/%v, expected: <= %v, actual: %v\n", name, c.name, limit, allocs)
+		}
+	}
+}
+
+func TestAllocations(t *testing.T) {
+	confusable := NewChecker(WithPreferredScripts(ScriptLatin), WithGreedy(true))
+	dangerous := NewChecker(WithPreferredScripts(ScriptLatin))
+	mixed := NewChecker()
+	cases := []struct {
+		name      string
+		f         func(str string)
+		maxAllocs map[string]float64
+	}{
+		{"IsConfusable", func(str string) { IsConfusable(str, true, []string{"latin"}) }, map[string]float64{
+			"ASCIIUsernames": 99, "ASCIIStretches": 65, "CJKText": 106, "MixedScript": 158,
+		}},
+		{"IsMixedScript", func(str string) { IsMixedScript(str, nil) }, map[string]float64{
+			"ASCIIUsernames": 0, "ASCIIStretches": 0, "CJKText": 0, "MixedScript": 0,
+		}},
+		{"IsDangerous", func(str string) { IsDangerous(str, []string{"latin"}) }, map[string]float64{
+			"ASCIIUsernames": 32, "ASCIIStretches": 33, "CJKText": 106, "MixedScript": 76,
+		}},
+		{"Checker.IsConfusable", func(str string) { confusable.IsConfusable(str) }, map[string]float64{
+			"ASCIIUsernames": 67, "ASCIIStretches": 57, "CJKText": 104, "MixedScript": 126,
+		}},
+		{"Checker.IsMixedScript", func(str string) { mixed.IsMixedScript(str) }, map[string]float64{
+			"ASCIIUsernames": 0, "ASCIIStretches": 0, "CJKText": 0, "MixedScript": 0,
+		}},
+		{"Checker.IsDangerous", func(str string) { dangerous.IsDangerous(str) }, map[string]float64{
+			"ASCIIUsernames": 0, "ASCIIStretches": 25, "CJKText": 104, "MixedScript": 44,
+		}},
+	}
+
+	for _, c := range cases {
+		testCorpusAllocations(t, c.name, c.f, c.maxAllocs)
+	}
+}
@@ -1,16 +1,21 @@
 package confusablehomoglyphs
 
 import (
-	"encoding/json"
+	"fmt"
+	"unicode/utf8"
 )
 
-var confusablesData = map[string][]Homoglyph{}
-var categoryData = CategoryData{}
-
+// CategoryData is the JSON format of the scripts and categories data, as
+// found in tools/categories.json.
 type CategoryData struct {
 	ISO15924aliases  []string `json:"iso_15924_aliases"`
 	Categories       []string `json:"categories"`
 	CodePointsRanges [][]int  `json:"code_points_ranges"`
+	// ScriptExtensions lists sets of indexes into ISO15924aliases.
+	// ScriptExtensionsRanges maps code points ranges to one of these sets,
+	// for characters used with other scripts than their own.
+	ScriptExtensions       [][]int `json:"script_extensions"`
+	ScriptExtensionsRanges [][]int `json:"script_extensions_ranges"`
 }
 
 type Homoglyph struct {
@@ -18,15 +23,218 @@ type Homoglyph struct {
 	N string `json:"n"`
 }
 
-func init() {
-	var err error
-	err = json.Unmarshal([]byte(confusablesJSONTXT), &confusablesData)
-	if err != nil {
-		panic("failed to parse confusables json")
+// DataInfo describes a dataset compiled into the package.
+type DataInfo struct {
+	// UnicodeVersion is the version of the Unicode Character Database and
+	// of UTS #39 the data comes from.
+	UnicodeVersion string `json:"unicode_version"`
+	// Sources are the Unicode data files the data was converted from, when
+	// known. It is empty for data obtained otherwise, Provenance then
+	// explains where it comes from.
+	Sources []SourceFile `json:"sources"`
+	// Provenance describes how the data was obtained when it was not
+	// converted from the Unicode files of Sources.
+	Provenance string `json:"provenance,omitempty"`
+	// Files are the data files of tools/data/<version> the dataset was
+	// generated from.
+	Files []SourceFile `json:"files"`
+
+	Confusables            int `json:"confusables"`
+	Homoglyphs             int `json:"homoglyphs"`
+	CodePointRanges        int `json:"code_point_ranges"`
+	ScriptExtensionsRanges int `json:"script_extensions_ranges"`
+}
+
+// SourceFile is a data file with its version and date, when its header
+// gives them, and its SHA-256 checksum.
+type SourceFile struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Date    string `json:"date,omitempty"`
+	SHA256  string `json:"sha256"`
+}
+
+// DataVersion returns the version and provenance of the newest dataset
+// compiled into the package, the one used by default, along with the number
+// of entries of its tables.
+func DataVersion() DataInfo {
+	return latestDataset().Info()
+}
+
+// scriptData holds the scripts and categories data of a Unicode version.
+type scriptData struct {
+	ranges           []codePointRange
+	extensionsRanges []scriptExtensionsRange
+	extensionsSets   [][]Script
+	// trie is the lookup table of ranges and extensionsRanges.
+	trie scriptTrie
+}
+
+// trieBlockBits is the number of low bits of the code points indexing them
+// in their block of scriptTrie.
+const trieBlockBits = 7
+
+// scriptTrie is a two-stage lookup table of the properties of every code
+// point, generated from the code points ranges: blocks maps the high bits
+// of a code point to the start of its block in values, divided by the block
+// size, and values maps it to its index in properties. Identical blocks are
+// shared.
+type scriptTrie struct {
+	blocks     []uint16
+	values     []uint16
+	properties []codePointProperties
+}
+
+// codePointProperties are the script, the general category and the script
+// extensions of a code point, as an index into scriptExtensionsSets or -1.
+type codePointProperties struct {
+	script     Script
+	category   GeneralCategory
+	extensions int16
+}
+
+// codePointRange gives the script and the general category of the code
+// points from first to last.
+type codePointRange struct {
+	first, last rune
+	script      Script
+	category    GeneralCategory
+}
+
+// scriptExtensionsRange gives the script extensions of the code points from
+// first to last, as an index into scriptExtensionsSets.
+type scriptExtensionsRange struct {
+	first, last rune
+	set         int
+}
+
+// confusables holds confusables data along with the indexes derived from
+// it. The package functions use the data compiled into the package, whose
+// indexes are generated with it, a Checker may use data loaded at runtime,
+// see NewCheckerFromSource.
+type confusables struct {
+	index confusablesIndex
+	// prototypes gives the prototype of the confusable class of characters,
+	// sorted by character. Characters which are prototypes themselves are
+	// not present.
+	prototypes []prototypeKey
+	// classes gives the other characters of the confusable class of
+	// prototypes as ranges of classMembers, sorted by prototype.
+	classes      []classKey
+	classMembers []string
+	// sequences are the keys longer than one character, sorted by their
+	// first character then longest first, and sequenceStarts gives the
+	// range of those starting with each character.
+	sequences      []string
+	sequenceStarts []runeKey
+	// ascii is the data of the ASCII characters for the scripts data
+	// asciiScripts, see withASCII. Without it, asciiScripts is nil and ASCII
+	// characters are looked up like the others.
+	ascii        [utf8.RuneSelf]asciiConfusable
+	asciiScripts *scriptData
+}
+
+// newConfusables checks index and builds the indexes derived from it.
+func newConfusables(index confusablesIndex) (*confusables, error) {
+	if err := validateIndex(index); err != nil {
+		return nil, err
 	}
 
-	err = json.Unmarshal([]byte(categoriesJSONTXT), &categoryData)
-	if err != nil {
-		panic("failed to parse categories json")
+	d := &confusables{index: index}
+	prototypes := d.buildPrototypes()
+	d.setClasses(prototypes, buildClasses(prototypes))
+	d.buildSequences()
+	return d, nil
+}
+
+// confusablesFromMap returns the confusables data of m, which maps
+// characters and sequences to their homoglyphs.
+func confusablesFromMap(m map[string][]Homoglyph) (*confusables, error) {
+	return newConfusables(buildIndex(m))
+}
+
+// Load checks the scripts data of the newest dataset and builds the tables
+// of the ASCII characters, its confusables data being checked when it is
+// generated. It is called on first use by the functions of the package,
+// calling it at start-up only moves that work earlier and lets callers
+// handle invalid data as an error. If the data is invalid, the functions of
+// the package behave as if no characters were confusable. See LoadDataset
+// for the other datasets.
+func Load() error {
+	return latestDataset().load()
+}
+
+// loadedConfusables returns the confusables data of the newest dataset.
+func loadedConfusables() *confusables {
+	return latestDataset().loadedConfusables()
+}
+
+// defaultScripts returns the scripts data of the newest dataset.
+func defaultScripts() *scriptData {
+	return latestDataset().scripts
+}
+
+func validateData(
+	ranges []codePointRange,
+	extensionsRanges []scriptExtensionsRange,
+	extensionsSets [][]Script,
+) error {
+	for i, r := range ranges {
+		if r.first > r.last || (i > 0 && ranges[i-1].last >= r.first) {
+			return fmt.Errorf("confusablehomoglyphs: code points range %04X-%04X out of order", r.first, r.last)
+		}
+		if int(r.script) >= len(scriptNames) || int(r.category) >= len(categoryNames) {
+			return fmt.Errorf("confusablehomoglyphs: unknown script or category for %04X-%04X", r.first, r.last)
+		}
+	}
+
+	for i, r := range extensionsRanges {
+		if r.first > r.last || (i > 0 && extensionsRanges[i-1].last >= r.first) {
+			return fmt.Errorf("confusablehomoglyphs: script extensions range %04X-%04X out of order", r.first, r.last)
+		}
+		if r.set < 0 || r.set >= len(extensionsSets) {
+			return fmt.Errorf("confusablehomoglyphs: unknown script extensions for %04X-%04X", r.first, r.last)
+		}
+	}
+
+	for i, set := range extensionsSets {
+		for _, s := range set {
+			if int(s) >= len(scriptNames) {
+				return fmt.Errorf("confusablehomoglyphs: unknown script in script extensions %d", i)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateTrie checks that the indexes of trie are in bounds.
+func validateTrie(trie scriptTrie, extensionsSets [][]Script) error {
+	if len(trie.blocks) != (utf8.MaxRune>>trieBlockBits)+1 {
+		return fmt.Errorf("confusablehomoglyphs: code points lookup table has %d blocks", len(trie.blocks))
+	}
+	for _, b := range trie.blocks {
+		if (int(b)+1)<<trieBlockBits > len(trie.values) {
+			return fmt.Errorf("confusablehomoglyphs: code points lookup table block %d out of bounds", b)
+		}
+	}
+	for _, v := range trie.values {
+		if int(v) >= len(trie.properties) {
+			return fmt.Errorf("confusablehomoglyphs: code points lookup table value %d out of bounds", v)
+		}
+	}
+	for _, p := range trie.properties {
+		if int(p.script) >= len(scriptNames) || int(p.category) >= len(categoryNames) ||
+			int(p.extensions) < -1 || int(p.extensions) >= len(extensionsSets) {
+			return fmt.Errorf("confusablehomoglyphs: unknown code point properties %v", p)
+		}
+	}
+	// ASCII characters are resolved by script, see scriptsAugmentedSet
+	for chr := rune(0); chr < utf8.RuneSelf; chr++ {
+		block := int(trie.blocks[chr>>trieBlockBits]) << trieBlockBits
+		if trie.properties[trie.values[block|int(chr)]].extensions >= 0 {
+			return fmt.Errorf("confusablehomoglyphs: unexpected script extensions for %04X", chr)
+		}
 	}
+	return nil
 }
@@ -0,0 +1,188 @@
+package confusablehomoglyphs
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLoad(t *testing.T) {
+	if err := Load(); err != nil {
+		t.Errorf("unexpected error, expected: %v, actual: %v\n", nil, err)
+	}
+}
+
+func TestValidateData(t *testing.T) {
+	ranges := []codePointRange{{0x41, 0x5A, ScriptLatin, CategoryLu}}
+	extensionsRanges := []scriptExtensionsRange{{0x30FC, 0x30FC, 0}}
+	extensionsSets := [][]Script{{ScriptHiragana, ScriptKatakana}}
+
+	cases := []struct {
+		name             string
+		ranges           []codePointRange
+		extensionsRanges []scriptExtensionsRange
+		extensionsSets   [][]Script
+		valid            bool
+	}{
+		{"valid", ranges, extensionsRanges, extensionsSets, true},
+		{"empty", nil, nil, nil, true},
+		{"reversed range", []codePointRange{{0x5A, 0x41, ScriptLatin, CategoryLu}}, extensionsRanges, extensionsSets, false},
+		{"overlapping ranges", append(ranges, codePointRange{0x5A, 0x5A, ScriptLatin, CategoryLu}), extensionsRanges, extensionsSets, false},
+		{"unknown script", []codePointRange{{0x41, 0x5A, Script(255), CategoryL}}, extensionsRanges, extensionsSets, false},
+		{"unknown script extensions", ranges, []scriptExtensionsRange{{0x30FC, 0x30FC, 1}}, extensionsSets, false},
+		{"unknown script in extensions", ranges, extensionsRanges, [][]Script{{Script(255)}}, false},
+	}
+
+	for _, c := range cases {
+		err := validateData(c.ranges, c.extensionsRanges, c.extensionsSets)
+		if (err == nil) != c.valid {
+			t.Errorf("unexpected validation, case: %v, expected valid: %v, actual: %v\n", c.name, c.valid, err)
+		}
+	}
+}
+
+func TestDataVersion(t *testing.T) {
+	info := DataVersion()
+	latest := Versions()[len(Versions())-1]
+	if info.UnicodeVersion != latest {
+		t.Errorf("unexpected unicode version, expected: %v, actual: %v\n", latest, info.UnicodeVersion)
+	}
+	if len(info.Sources) == 0 && info.Provenance == "" {
+		t.Errorf("unexpected provenance, expected sources or a provenance note\n")
+	}
+	if len(info.Files) != 4 {
+		t.Errorf("unexpected files, expected: %v, actual: %v\n", 4, len(info.Files))
+	}
+	for _, f := range info.Files {
+		if len(f.SHA256) != 64 {
+			t.Errorf("unexpected checksum, file: %v, actual: %q\n", f.Name, f.SHA256)
+		}
+	}
+	index := latestDataset().data.index
+	if info.Confusables != index.len() || info.Homoglyphs != len(index.homoglyphs) {
+		t.Errorf("unexpected confusables count, expected: %v, actual: %v\n", index.len(), info.Confusables)
+	}
+	scripts := latestDataset().scripts
+	if info.CodePointRanges != len(scripts.ranges) || info.ScriptExtensionsRanges != len(scripts.extensionsRanges) {
+		t.Errorf("unexpected ranges count, expected: %v, %v, actual: %v, %v\n", len(scripts.ranges), len(scripts.extensionsRanges), info.CodePointRanges, info.ScriptExtensionsRanges)
+	}
+
+	info.Files[0].Name = ""
+	if DataVersion().Files[0].Name == "" {
+		t.Errorf("unexpected shared files\n")
+	}
+}
+
+func TestEmbeddedData(t *testing.T) {
+	for _, d := range datasets {
+		s := d.scripts
+		if err := validateData(s.ranges, s.extensionsRanges, s.extensionsSets); err != nil {
+			t.Errorf("unexpected invalid scripts data, version: %v, error: %v\n", d.Version(), err)
+		}
+		if err := validateTrie(s.trie, s.extensionsSets); err != nil {
+			t.Errorf("unexpected invalid lookup table, version: %v, error: %v\n", d.Version(), err)
+		}
+		// the generated indexes are the ones built for runtime data
+		data, err := newConfusables(d.data.index)
+		if err != nil {
+			t.Errorf("unexpected invalid confusables data, version: %v, error: %v\n", d.Version(), err)
+		} else {
+			if !reflect.DeepEqual(d.data.prototypes, data.prototypes) {
+				t.Errorf("unexpected generated prototypes, version: %v\n", d.Version())
+			}
+			if !reflect.DeepEqual(d.data.classes, data.classes) || !reflect.DeepEqual(d.data.classMembers, data.classMembers) {
+				t.Errorf("unexpected generated classes, version: %v\n", d.Version())
+			}
+			if !reflect.DeepEqual(d.data.sequences, data.sequences) || !reflect.DeepEqual(d.data.sequenceStarts, data.sequenceStarts) {
+				t.Errorf("unexpected generated sequences, version: %v\n", d.Version())
+			}
+		}
+
+		// gaps between ranges are unassigned code points
+		d.data.index.forEach(func(key string, _ []homoglyphRef) {
+			for _, chr := range key {
+				if script, _ := s.scriptCategory(chr); script == ScriptUnknown {
+					t.Errorf("unexpected unassigned confusable, version: %v, confusable: %+q\n", d.Version(), key)
+				}
+			}
+		})
+		for _, r := range s.extensionsRanges {
+			for chr := r.first; chr <= r.last; chr++ {
+				if script, _ := s.scriptCategory(chr); script == ScriptUnknown {
+					t.Errorf("unexpected unassigned script extensions, version: %v, code point: %U\n", d.Version(), chr)
+				}
+			}
+		}
+	}
+}
+
+func TestEmbeddedTrie(t *testing.T) {
+	for _, d := range datasets {
+		s := d.scripts
+		// the lookup table agrees with the ranges for every code point
+		expected := make([]codePointProperties, utf8.MaxRune+1)
+		for chr := range expected {
+			expected[chr] = codePointProperties{ScriptUnknown, CategoryUnknown, -1}
+		}
+		for _, r := range s.ranges {
+			for chr := r.first; chr <= r.last; chr++ {
+				expected[chr].script, expected[chr].category = r.script, r.category
+			}
+		}
+		for _, r := range s.extensionsRanges {
+			for chr := r.first; chr <= r.last; chr++ {
+				expected[chr].extensions = int16(r.set)
+			}
+		}
+
+		for chr, p := range expected {
+			if actual := s.properties(rune(chr)); actual != p {
+				t.Errorf("unexpected code point properties, version: %v, code point: %U, expected: %v, actual: %v\n", d.Version(), chr, p, actual)
+				break
+			}
+		}
+	}
+}
+
+func TestValidateTrie(t *testing.T) {
+	valid := defaultScripts().trie
+	extensionsSets := defaultScripts().extensionsSets
+
+	withBlock := append([]uint16{}, valid.blocks...)
+	withBlock[0] = uint16(len(valid.values))
+	withValue := append([]uint16{}, valid.values...)
+	withValue[0] = uint16(len(valid.properties))
+	withProperties := append([]codePointProperties{}, valid.properties...)
+	withProperties[0].extensions = int16(len(extensionsSets))
+
+	cases := []struct {
+		name  string
+		trie  scriptTrie
+		valid bool
+	}{
+		{"valid", valid, true},
+		{"missing blocks", scriptTrie{valid.blocks[1:], valid.values, valid.properties}, false},
+		{"block out of bounds", scriptTrie{withBlock, valid.values, valid.properties}, false},
+		{"value out of bounds", scriptTrie{valid.blocks, withValue, valid.properties}, false},
+		{"unknown script extensions", scriptTrie{valid.blocks, valid.values, withProperties}, false},
+	}
+
+	for _, c := range cases {
+		err := validateTrie(c.trie, extensionsSets)
+		if (err == nil) != c.valid {
+			t.Errorf("unexpected validation, case: %v, expected valid: %v, actual: %v\n", c.name, c.valid, err)
+		}
+	}
+}
+
+func BenchmarkLoad(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, d := range datasets {
+			data := &Dataset{info: d.info, scripts: d.scripts, data: d.data}
+			if err := data.load(); err != nil {
+				b.Fatalf("unexpected error, expected: %v, actual: %v\n", nil, err)
+			}
+		}
+	}
+}
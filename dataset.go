@@ -0,0 +1,98 @@
+package confusablehomoglyphs
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Dataset is the confusables and scripts data of a Unicode version compiled
+// into the package. Checkers can be pinned to a dataset with WithDataset,
+// e.g. to keep the verdicts on stored identifiers stable across upgrades of
+// the Unicode data.
+type Dataset struct {
+	info    DataInfo
+	scripts *scriptData
+	// data is the confusables data with its indexes, which are generated
+	// and checked along with it, see TestEmbeddedData.
+	data *confusables
+
+	once        sync.Once
+	err         error
+	confusables *confusables
+}
+
+// Versions returns the Unicode versions of the datasets compiled into the
+// package, oldest first. The last one is used by default.
+func Versions() []string {
+	versions := make([]string, len(datasets))
+	for i, d := range datasets {
+		versions[i] = d.info.UnicodeVersion
+	}
+	return versions
+}
+
+// LoadDataset returns the dataset of the Unicode version, one of Versions,
+// once it is loaded, see Load.
+func LoadDataset(version string) (*Dataset, error) {
+	for _, d := range datasets {
+		if d.info.UnicodeVersion == version {
+			return d, d.load()
+		}
+	}
+	return nil, fmt.Errorf("confusablehomoglyphs: unknown data version %q", version)
+}
+
+// WithDataset makes the Checker use the data of d instead of the newest
+// dataset. The confusables data of NewCheckerFromSource is used over the one
+// of d.
+func WithDataset(d *Dataset) Option {
+	return func(c *Checker) {
+		c.scripts = d.scripts
+		c.data = d.loadedConfusables()
+	}
+}
+
+// Version returns the Unicode version of d.
+func (d *Dataset) Version() string {
+	return d.info.UnicodeVersion
+}
+
+// Info returns the version and provenance of d, along with the number of
+// entries of its tables.
+func (d *Dataset) Info() DataInfo {
+	info := d.info
+	info.Sources = append([]SourceFile{}, d.info.Sources...)
+	info.Files = append([]SourceFile{}, d.info.Files...)
+	info.Confusables = d.data.index.len()
+	info.Homoglyphs = len(d.data.index.homoglyphs)
+	info.CodePointRanges = len(d.scripts.ranges)
+	info.ScriptExtensionsRanges = len(d.scripts.extensionsRanges)
+	return info
+}
+
+func (d *Dataset) load() error {
+	d.once.Do(func() {
+		d.confusables = &confusables{}
+		d.err = validateData(d.scripts.ranges, d.scripts.extensionsRanges, d.scripts.extensionsSets)
+		if d.err != nil {
+			return
+		}
+		d.err = validateTrie(d.scripts.trie, d.scripts.extensionsSets)
+		if d.err != nil {
+			return
+		}
+		d.confusables = d.data.withASCII(d.scripts)
+	})
+	return d.err
+}
+
+// loadedConfusables returns the confusables data of d, empty if it is
+// invalid.
+func (d *Dataset) loadedConfusables() *confusables {
+	d.load()
+	return d.confusables
+}
+
+func latestDataset() *Dataset {
+	return datasets[len(datasets)-1]
+}
@@ -0,0 +1,55 @@
+package confusablehomoglyphs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVersions(t *testing.T) {
+	versions := Versions()
+	if len(versions) == 0 || versions[len(versions)-1] != DataVersion().UnicodeVersion {
+		t.Errorf("unexpected versions, expected last: %v, actual: %v\n", DataVersion().UnicodeVersion, versions)
+	}
+
+	for _, v := range versions {
+		d, err := LoadDataset(v)
+		if err != nil {
+			t.Errorf("unexpected error, version: %v, actual: %v\n", v, err)
+			continue
+		}
+		if d.Version() != v || d.Info().Confusables == 0 {
+			t.Errorf("unexpected dataset, version: %v, actual: %v\n", v, d.Info())
+		}
+	}
+
+	if _, err := LoadDataset("1.0.0"); err == nil {
+		t.Errorf("unexpected success for unknown version\n")
+	}
+}
+
+func TestWithDataset(t *testing.T) {
+	d, err := LoadDataset("11.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	checker := NewChecker(WithDataset(d), WithPreferredAliases("latin"))
+	if skeleton := checker.MapPrototypes("pаypal"); skeleton != "paypal" {
+		t.Errorf("unexpected skeleton, expected: %q, actual: %q\n", "paypal", skeleton)
+	}
+	if count := len(checker.IsConfusable("pаypal")); count != 1 {
+		t.Errorf("unexpected confusables count, expected: %v, actual: %v\n", 1, count)
+	}
+	if !checker.IsMixedScriptConfusable("pаypal") {
+		t.Errorf("unexpected IsMixedScriptConfusable, expected: %v, actual: %v\n", true, false)
+	}
+
+	// the confusables data of the source is used with the scripts of d
+	checker, err = NewCheckerFromSource(ConfusablesTXT(strings.NewReader(testConfusablesTXT)), WithDataset(d))
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	if skeleton := checker.MapPrototypes("ρаypal"); skeleton != "ρaypal" {
+		t.Errorf("unexpected skeleton, expected: %q, actual: %q\n", "ρaypal", skeleton)
+	}
+}
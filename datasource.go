@@ -0,0 +1,189 @@
+package confusablehomoglyphs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DataSource provides confusables data to use instead of the data compiled
+// into the package, e.g. a newer or patched mapping, see
+// NewCheckerFromSource. The scripts and categories data stay the compiled
+// ones.
+type DataSource interface {
+	// Confusables returns every character or sequence mapped to the
+	// homoglyphs it is confusable with. The mapping must be symmetric.
+	Confusables() (map[string][]Homoglyph, error)
+}
+
+// readerSource reads its reader once and keeps the data, or the error, for
+// the later calls to Confusables.
+type readerSource struct {
+	r     io.Reader
+	parse func(io.Reader) (map[string][]Homoglyph, error)
+
+	once sync.Once
+	m    map[string][]Homoglyph
+	err  error
+}
+
+func (s *readerSource) Confusables() (map[string][]Homoglyph, error) {
+	s.once.Do(func() {
+		s.m, s.err = s.parse(s.r)
+		s.r = nil
+	})
+	if s.err != nil {
+		return nil, s.err
+	}
+	// callers may modify the map they get
+	m := make(map[string][]Homoglyph, len(s.m))
+	for k, homoglyphs := range s.m {
+		m[k] = append([]Homoglyph{}, homoglyphs...)
+	}
+	return m, nil
+}
+
+type fileSource string
+
+func (s fileSource) Confusables() (map[string][]Homoglyph, error) {
+	f, err := os.Open(string(s))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(string(s)), ".json") {
+		return parseConfusablesJSON(f)
+	}
+	return parseConfusablesTXT(f)
+}
+
+// ConfusablesJSON reads confusables data from r in the format of
+// tools/confusables.json. r is read once, by the first call to Confusables,
+// and the data is kept: later calls return it again, or the same error, so
+// the source can be used for several Checkers.
+func ConfusablesJSON(r io.Reader) DataSource {
+	return &readerSource{r: r, parse: parseConfusablesJSON}
+}
+
+// ConfusablesTXT reads confusables data from r in the format of the
+// confusables.txt file of UTS #39, as published on
+// https://www.unicode.org/Public/security/. r is read once and the data
+// kept, as for ConfusablesJSON.
+func ConfusablesTXT(r io.Reader) DataSource {
+	return &readerSource{r: r, parse: parseConfusablesTXT}
+}
+
+// ConfusablesFile reads confusables data from the file at path, every time
+// Confusables is called. Files with a .json extension are read as
+// ConfusablesJSON, other files as ConfusablesTXT.
+func ConfusablesFile(path string) DataSource {
+	return fileSource(path)
+}
+
+// NewCheckerFromSource returns a Checker configured with options which uses
+// the confusables data from source.
+func NewCheckerFromSource(source DataSource, options ...Option) (*Checker, error) {
+	m, err := source.Confusables()
+	if err != nil {
+		return nil, err
+	}
+	data, err := confusablesFromMap(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return newChecker(data, options), nil
+}
+
+func parseConfusablesJSON(r io.Reader) (map[string][]Homoglyph, error) {
+	m := map[string][]Homoglyph{}
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("confusablehomoglyphs: invalid confusables json: %v", err)
+	}
+	return m, nil
+}
+
+// parseConfusablesTXT parses confusables.txt into a symmetric mapping: both
+// the source and its prototype list each other. The names of the characters
+// are taken from the comment of each line, e.g.
+// "0430 ;	0061 ;	MA	# ( а → a ) CYRILLIC SMALL LETTER A → LATIN SMALL LETTER A	#".
+func parseConfusablesTXT(r io.Reader) (map[string][]Homoglyph, error) {
+	m := map[string][]Homoglyph{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimPrefix(scanner.Text(), "\ufeff")
+		comment := ""
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text, comment = text[:i], text[i+1:]
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		fields := strings.Split(text, ";")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("confusablehomoglyphs: confusables.txt:%d: invalid line", line)
+		}
+		source, err := parseCodePoints(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("confusablehomoglyphs: confusables.txt:%d: %v", line, err)
+		}
+		target, err := parseCodePoints(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("confusablehomoglyphs: confusables.txt:%d: %v", line, err)
+		}
+
+		sourceName, targetName := commentNames(comment)
+		m[source] = append(m[source], Homoglyph{C: target, N: targetName})
+		m[target] = append(m[target], Homoglyph{C: source, N: sourceName})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// parseCodePoints parses a space separated sequence of hexadecimal code
+// points.
+func parseCodePoints(s string) (string, error) {
+	var b strings.Builder
+	for _, f := range strings.Fields(s) {
+		cp, err := strconv.ParseUint(f, 16, 32)
+		if err != nil || cp > 0x10FFFF {
+			return "", fmt.Errorf("invalid code point %q", f)
+		}
+		b.WriteRune(rune(cp))
+	}
+	if b.Len() == 0 {
+		return "", fmt.Errorf("empty code points sequence")
+	}
+	return b.String(), nil
+}
+
+// commentNames returns the names of the source and of the target from the
+// comment of a confusables.txt line, or empty names if it has none. Names
+// never contain parentheses while the characters before them may.
+func commentNames(comment string) (string, string) {
+	i := strings.LastIndex(comment, ") ")
+	if i < 0 {
+		return "", ""
+	}
+	comment = comment[i+2:]
+	if i := strings.IndexByte(comment, '#'); i >= 0 {
+		comment = comment[:i]
+	}
+	names := strings.SplitN(comment, "→", 2)
+	if len(names) != 2 {
+		return "", ""
+	}
+	return strings.TrimSpace(names[0]), strings.TrimSpace(names[1])
+}
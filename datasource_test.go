@@ -0,0 +1,153 @@
+package confusablehomoglyphs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testConfusablesTXT = "\ufeff# confusables.txt\n" +
+	"0430 ;\t0061 ;\tMA\t# ( а → a ) CYRILLIC SMALL LETTER A → LATIN SMALL LETTER A\t# \n" +
+	"0251 ;\t0061 ;\tMA\t# ( ɑ → a ) LATIN SMALL LETTER ALPHA → LATIN SMALL LETTER A\t# \n" +
+	"\n" +
+	"2E28 ;\t0028 0028 ;\tMA\t# ( ⸨ → (( ) LEFT DOUBLE PARENTHESIS → LEFT PARENTHESIS, LEFT PARENTHESIS\t# \n"
+
+func TestConfusablesTXT(t *testing.T) {
+	m, err := ConfusablesTXT(strings.NewReader(testConfusablesTXT)).Confusables()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	expected := map[string][]Homoglyph{
+		"а":  {{"a", "LATIN SMALL LETTER A"}},
+		"ɑ":  {{"a", "LATIN SMALL LETTER A"}},
+		"a":  {{"а", "CYRILLIC SMALL LETTER A"}, {"ɑ", "LATIN SMALL LETTER ALPHA"}},
+		"⸨":  {{"((", "LEFT PARENTHESIS, LEFT PARENTHESIS"}},
+		"((": {{"⸨", "LEFT DOUBLE PARENTHESIS"}},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("unexpected confusables, expected: %v, actual: %v\n", expected, m)
+	}
+
+	for _, txt := range []string{"0430\n", "0430 ; 00ZZ ; MA\n", "0430 ; ; MA\n"} {
+		if _, err := ConfusablesTXT(strings.NewReader(txt)).Confusables(); err == nil {
+			t.Errorf("unexpected success, txt: %q\n", txt)
+		}
+	}
+}
+
+func TestConfusablesJSON(t *testing.T) {
+	json := `{"a": [{"c": "а", "n": "CYRILLIC SMALL LETTER A"}], "а": [{"c": "a", "n": "LATIN SMALL LETTER A"}]}`
+	m, err := ConfusablesJSON(strings.NewReader(json)).Confusables()
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	if len(m) != 2 || m["a"][0].C != "а" {
+		t.Errorf("unexpected confusables, actual: %v\n", m)
+	}
+
+	if _, err := ConfusablesJSON(strings.NewReader("[")).Confusables(); err == nil {
+		t.Errorf("unexpected success for invalid json\n")
+	}
+}
+
+func TestReaderSourceReuse(t *testing.T) {
+	sources := []DataSource{
+		ConfusablesTXT(strings.NewReader(testConfusablesTXT)),
+		ConfusablesJSON(strings.NewReader(`{"a": [{"c": "а", "n": "CYRILLIC SMALL LETTER A"}], "а": [{"c": "a", "n": "LATIN SMALL LETTER A"}]}`)),
+	}
+
+	// the reader is read once, later loads get the same data
+	for _, source := range sources {
+		first, err := source.Confusables()
+		if err != nil {
+			t.Fatalf("unexpected error: %v\n", err)
+		}
+		expected := len(first)
+		first["a"][0].C = "x"
+		delete(first, "а")
+
+		for i := 0; i < 2; i++ {
+			checker, err := NewCheckerFromSource(source, WithPreferredScripts(ScriptLatin))
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n", err)
+			}
+			if count := checker.data.index.len(); count != expected {
+				t.Errorf("unexpected confusables count, expected: %v, actual: %v\n", expected, count)
+			}
+			if count := len(checker.IsConfusable("pаypal")); count != 1 {
+				t.Errorf("unexpected confusables, expected: %v, actual: %v\n", 1, count)
+			}
+		}
+	}
+
+	source := ConfusablesJSON(strings.NewReader("["))
+	for i := 0; i < 2; i++ {
+		if _, err := source.Confusables(); err == nil {
+			t.Errorf("unexpected success for invalid json, call: %v\n", i)
+		}
+	}
+}
+
+func TestConfusablesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "confusables")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "confusables.txt")
+	if err := ioutil.WriteFile(path, []byte(testConfusablesTXT), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := ConfusablesFile(path).Confusables()
+	if err != nil || len(m) != 5 {
+		t.Errorf("unexpected confusables, actual: %v, error: %v\n", m, err)
+	}
+
+	if _, err := ConfusablesFile(filepath.Join(dir, "missing.json")).Confusables(); err == nil {
+		t.Errorf("unexpected success for missing file\n")
+	}
+}
+
+func TestNewCheckerFromSource(t *testing.T) {
+	checker, err := NewCheckerFromSource(ConfusablesTXT(strings.NewReader(testConfusablesTXT)), WithPreferredAliases("latin"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+
+	cases := []struct {
+		str      string
+		skeleton string
+		count    int
+	}{
+		{"pаypal", "paypal", 1},
+		// "((" is not Latin
+		{"⸨a", "((a", 0},
+		// ρ is only confusable with the compiled-in data
+		{"ρaypal", "ρaypal", 0},
+	}
+
+	for _, c := range cases {
+		skeleton := checker.MapPrototypes(c.str)
+		if skeleton != c.skeleton {
+			t.Errorf("unexpected skeleton, string: %v, expected: %q, actual: %q\n", c.str, c.skeleton, skeleton)
+		}
+		count := len(checker.IsConfusable(c.str))
+		if count != c.count {
+			t.Errorf("unexpected confusables count, string: %v, expected: %v, actual: %v\n", c.str, c.count, count)
+		}
+	}
+
+	if ok, _ := checker.AreConfusable("pаypal", "paypal"); !ok {
+		t.Errorf("unexpected AreConfusable, expected: %v, actual: %v\n", true, ok)
+	}
+
+	asymmetric := `{"a": [{"c": "а", "n": "CYRILLIC SMALL LETTER A"}]}`
+	if _, err := NewCheckerFromSource(ConfusablesJSON(strings.NewReader(asymmetric))); err == nil {
+		t.Errorf("unexpected success for asymmetric data\n")
+	}
+}
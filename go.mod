@@ -1,3 +1,5 @@
 module github.com/skygeario/go-confusable-homoglyphs
 
 go 1.12
+
+require golang.org/x/text v0.3.8
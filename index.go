@@ -0,0 +1,256 @@
+package confusablehomoglyphs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// confusablesIndex is the compact form of confusables data. Single
+// characters are keyed by code point, sequences by string. Both give their
+// homoglyphs as a range of homoglyphs, whose characters and names are
+// stored once in a pool of strings.
+type confusablesIndex struct {
+	runes      []runeKey
+	keys       []confusableKey
+	homoglyphs []homoglyphRef
+	strs       stringPool
+}
+
+// runeKey gives the homoglyphs of the character chr as the range from start
+// to end of the homoglyphs of the index.
+type runeKey struct {
+	chr        rune
+	start, end uint32
+}
+
+// confusableKey gives the homoglyphs of key as the range from start to end
+// of the homoglyphs of the index.
+type confusableKey struct {
+	key        string
+	start, end uint32
+}
+
+// homoglyphRef is a homoglyph as the indexes of its character and of its
+// name in the string pool.
+type homoglyphRef struct {
+	c, n uint32
+}
+
+// stringPool stores strings back to back in data, ends giving the offset
+// of the end of each of them.
+type stringPool struct {
+	data string
+	ends []uint32
+}
+
+func (p *stringPool) get(i uint32) string {
+	start := uint32(0)
+	if i > 0 {
+		start = p.ends[i-1]
+	}
+	return p.data[start:p.ends[i]]
+}
+
+// buildIndex returns the index of m, which maps characters and sequences to
+// their homoglyphs. The LEFT-TO-RIGHT MARKs the confusables data wraps
+// right-to-left characters in are removed, so that they are keyed and
+// listed as the characters they are.
+func buildIndex(m map[string][]Homoglyph) confusablesIndex {
+	m = trimLRMs(m)
+	strs := make([]string, 0, len(m))
+	for k := range m {
+		strs = append(strs, k)
+	}
+	sort.Strings(strs)
+
+	x := confusablesIndex{}
+	var data strings.Builder
+	ids := map[string]uint32{}
+	intern := func(str string) uint32 {
+		id, ok := ids[str]
+		if !ok {
+			data.WriteString(str)
+			id = uint32(len(x.strs.ends))
+			ids[str] = id
+			x.strs.ends = append(x.strs.ends, uint32(data.Len()))
+		}
+		return id
+	}
+
+	// single characters sort in code point order like their UTF-8 encoding
+	for _, k := range strs {
+		start := uint32(len(x.homoglyphs))
+		for _, h := range m[k] {
+			x.homoglyphs = append(x.homoglyphs, homoglyphRef{c: intern(h.C), n: intern(h.N)})
+		}
+		end := uint32(len(x.homoglyphs))
+		if chr, ok := singleRune(k); ok {
+			x.runes = append(x.runes, runeKey{chr: chr, start: start, end: end})
+		} else {
+			x.keys = append(x.keys, confusableKey{key: k, start: start, end: end})
+		}
+	}
+	x.strs.data = data.String()
+	return x
+}
+
+// trimLRMs returns m with the LEFT-TO-RIGHT MARKs removed from its keys
+// and the characters of their homoglyphs, merging what they then share.
+func trimLRMs(m map[string][]Homoglyph) map[string][]Homoglyph {
+	trimmed := make(map[string][]Homoglyph, len(m))
+	for k, homoglyphs := range m {
+		key := trimLRM(k)
+	HOMOGLYPHS:
+		for _, h := range homoglyphs {
+			h.C = trimLRM(h.C)
+			for _, other := range trimmed[key] {
+				if other.C == h.C {
+					continue HOMOGLYPHS
+				}
+			}
+			trimmed[key] = append(trimmed[key], h)
+		}
+	}
+	return trimmed
+}
+
+// trimLRM removes the LEFT-TO-RIGHT MARKs wrapped around right-to-left
+// characters in the confusables data.
+func trimLRM(str string) string {
+	return strings.Trim(str, "\u200e")
+}
+
+// singleRune returns the character str is made of, if it is a single valid
+// character.
+func singleRune(str string) (rune, bool) {
+	chr, size := utf8.DecodeRuneInString(str)
+	if size == 0 || size != len(str) || (chr == utf8.RuneError && size == 1) {
+		return 0, false
+	}
+	return chr, true
+}
+
+// len returns the number of keys of x.
+func (x *confusablesIndex) len() int {
+	return len(x.runes) + len(x.keys)
+}
+
+// lookup returns the homoglyphs of key.
+func (x *confusablesIndex) lookup(key string) ([]homoglyphRef, bool) {
+	if chr, ok := singleRune(key); ok {
+		return x.lookupRune(chr)
+	}
+	i := sort.Search(len(x.keys), func(i int) bool {
+		return x.keys[i].key >= key
+	})
+	if i == len(x.keys) || x.keys[i].key != key {
+		return nil, false
+	}
+	k := x.keys[i]
+	return x.homoglyphs[k.start:k.end:k.end], true
+}
+
+// lookupRune returns the homoglyphs of the character chr.
+func (x *confusablesIndex) lookupRune(chr rune) ([]homoglyphRef, bool) {
+	i := sort.Search(len(x.runes), func(i int) bool {
+		return x.runes[i].chr >= chr
+	})
+	if i == len(x.runes) || x.runes[i].chr != chr {
+		return nil, false
+	}
+	k := x.runes[i]
+	return x.homoglyphs[k.start:k.end:k.end], true
+}
+
+// char returns the character of the homoglyph h.
+func (x *confusablesIndex) char(h homoglyphRef) string {
+	return x.strs.get(h.c)
+}
+
+// materialize returns the homoglyphs refs.
+func (x *confusablesIndex) materialize(refs []homoglyphRef) []Homoglyph {
+	homoglyphs := make([]Homoglyph, len(refs))
+	for i, h := range refs {
+		homoglyphs[i] = Homoglyph{C: x.strs.get(h.c), N: x.strs.get(h.n)}
+	}
+	return homoglyphs
+}
+
+// lists checks if str is one of the homoglyphs of key.
+func (x *confusablesIndex) lists(key string, str string) bool {
+	refs, _ := x.lookup(key)
+	for _, h := range refs {
+		if x.char(h) == str {
+			return true
+		}
+	}
+	return false
+}
+
+// forEach calls f with every key of x and its homoglyphs, the single
+// characters first.
+func (x *confusablesIndex) forEach(f func(key string, homoglyphs []homoglyphRef)) {
+	for _, k := range x.runes {
+		f(string(k.chr), x.homoglyphs[k.start:k.end:k.end])
+	}
+	for _, k := range x.keys {
+		f(k.key, x.homoglyphs[k.start:k.end:k.end])
+	}
+}
+
+// validateIndex checks that the keys of x are sorted, that its indexes are
+// in bounds and that the data is symmetric: every homoglyph of a key has
+// the key among its own homoglyphs.
+func validateIndex(x confusablesIndex) error {
+	for i, end := range x.strs.ends {
+		if int(end) > len(x.strs.data) || (i > 0 && x.strs.ends[i-1] > end) {
+			return fmt.Errorf("confusablehomoglyphs: string %d out of bounds", i)
+		}
+	}
+	for _, h := range x.homoglyphs {
+		if int(h.c) >= len(x.strs.ends) || int(h.n) >= len(x.strs.ends) {
+			return fmt.Errorf("confusablehomoglyphs: homoglyph string out of bounds")
+		}
+	}
+
+	for i, k := range x.runes {
+		if !utf8.ValidRune(k.chr) {
+			return fmt.Errorf("confusablehomoglyphs: invalid confusable %U", k.chr)
+		}
+		if i > 0 && x.runes[i-1].chr >= k.chr {
+			return fmt.Errorf("confusablehomoglyphs: confusable %q out of order", k.chr)
+		}
+		if k.start >= k.end || int(k.end) > len(x.homoglyphs) {
+			return fmt.Errorf("confusablehomoglyphs: no homoglyphs for confusable %q", k.chr)
+		}
+	}
+	for i, k := range x.keys {
+		if _, ok := singleRune(k.key); ok || k.key == "" || !utf8.ValidString(k.key) || trimLRM(k.key) != k.key {
+			return fmt.Errorf("confusablehomoglyphs: invalid confusable %q", k.key)
+		}
+		if i > 0 && x.keys[i-1].key >= k.key {
+			return fmt.Errorf("confusablehomoglyphs: confusable %q out of order", k.key)
+		}
+		if k.start >= k.end || int(k.end) > len(x.homoglyphs) {
+			return fmt.Errorf("confusablehomoglyphs: no homoglyphs for confusable %q", k.key)
+		}
+	}
+
+	var err error
+	x.forEach(func(key string, homoglyphs []homoglyphRef) {
+		for _, h := range homoglyphs {
+			if err != nil {
+				return
+			}
+			c := x.char(h)
+			if c == "" || !utf8.ValidString(c) || trimLRM(c) != c {
+				err = fmt.Errorf("confusablehomoglyphs: invalid homoglyph %q of confusable %q", c, key)
+			} else if !x.lists(c, key) {
+				err = fmt.Errorf("confusablehomoglyphs: confusable %q missing from homoglyph %q", key, c)
+			}
+		}
+	})
+	return err
+}
@@ -0,0 +1,105 @@
+package confusablehomoglyphs
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testIndexData = map[string][]Homoglyph{
+	"a":             {{"ɑ", "LATIN SMALL LETTER ALPHA"}},
+	"ɑ":             {{"a", "LATIN SMALL LETTER A"}},
+	"m":             {{"rn", "LATIN SMALL LETTER R, LATIN SMALL LETTER N"}},
+	"rn":            {{"m", "LATIN SMALL LETTER M"}},
+	"d":             {{"cl", "LATIN SMALL LETTER C, LATIN SMALL LETTER L"}},
+	"cl":            {{"d", "LATIN SMALL LETTER D"}},
+	"\u200eו\u200e": {{"l", "LATIN SMALL LETTER L"}},
+	"l":             {{"\u200eו\u200e", "HEBREW LETTER VAV"}},
+}
+
+func TestBuildIndex(t *testing.T) {
+	x := buildIndex(testIndexData)
+	if len(x.runes) != 6 || len(x.keys) != 2 {
+		t.Errorf("unexpected keys, expected: %v %v, actual: %v %v\n", 6, 2, len(x.runes), len(x.keys))
+	}
+
+	// right-to-left characters are found without their LEFT-TO-RIGHT MARKs
+	for key, homoglyphs := range testIndexData {
+		key = trimLRM(key)
+		expected := []Homoglyph{}
+		for _, h := range homoglyphs {
+			expected = append(expected, Homoglyph{C: trimLRM(h.C), N: h.N})
+		}
+		refs, ok := x.lookup(key)
+		if homoglyphs := x.materialize(refs); !ok || !reflect.DeepEqual(homoglyphs, expected) {
+			t.Errorf("unexpected homoglyphs, key: %q, expected: %v, actual: %v %v\n", key, expected, homoglyphs, ok)
+		}
+	}
+	if _, ok := x.lookupRune('ו'); !ok {
+		t.Errorf("unexpected missing homoglyphs, key: %q\n", 'ו')
+	}
+	if _, ok := x.lookup("\u200eו\u200e"); ok {
+		t.Errorf("unexpected homoglyphs, key: %q\n", "\u200eו\u200e")
+	}
+	if _, ok := x.lookup("b"); ok {
+		t.Errorf("unexpected homoglyphs, key: %q\n", "b")
+	}
+}
+
+func TestTrimLRMs(t *testing.T) {
+	m := map[string][]Homoglyph{
+		"\u200eו\u200e": {{"l", "LATIN SMALL LETTER L"}, {"\u200eן\u200e", "HEBREW LETTER FINAL NUN"}},
+		"ו":             {{"ן", "HEBREW LETTER FINAL NUN"}, {"|", "VERTICAL LINE"}},
+	}
+	expected := map[string][]Homoglyph{
+		"ו": {{"l", "LATIN SMALL LETTER L"}, {"ן", "HEBREW LETTER FINAL NUN"}, {"|", "VERTICAL LINE"}},
+	}
+
+	// keys only differing by their LEFT-TO-RIGHT MARKs are merged
+	trimmed := trimLRMs(m)
+	if len(trimmed) != 1 || len(trimmed["ו"]) != 3 {
+		t.Fatalf("unexpected trimmed data, expected: %v, actual: %v\n", expected, trimmed)
+	}
+	for _, h := range expected["ו"] {
+		found := false
+		for _, other := range trimmed["ו"] {
+			found = found || other == h
+		}
+		if !found {
+			t.Errorf("unexpected trimmed data, expected: %v, actual: %v\n", expected, trimmed)
+		}
+	}
+}
+
+func TestValidateIndex(t *testing.T) {
+	valid := buildIndex(testIndexData)
+	withIndex := func(f func(x *confusablesIndex)) confusablesIndex {
+		x := buildIndex(testIndexData)
+		f(&x)
+		return x
+	}
+
+	cases := []struct {
+		name  string
+		index confusablesIndex
+		valid bool
+	}{
+		{"valid", valid, true},
+		{"empty", confusablesIndex{}, true},
+		{"unsorted runes", withIndex(func(x *confusablesIndex) { x.runes[0], x.runes[1] = x.runes[1], x.runes[0] }), false},
+		{"unsorted keys", withIndex(func(x *confusablesIndex) { x.keys[0], x.keys[1] = x.keys[1], x.keys[0] }), false},
+		{"no homoglyphs", withIndex(func(x *confusablesIndex) { x.runes[0].end = x.runes[0].start }), false},
+		{"homoglyphs out of bounds", withIndex(func(x *confusablesIndex) { x.keys[1].end = 100 }), false},
+		{"invalid key", withIndex(func(x *confusablesIndex) { x.keys[0].key = "\xff" }), false},
+		{"single character key", withIndex(func(x *confusablesIndex) { x.keys[0].key = "b" }), false},
+		{"invalid rune", withIndex(func(x *confusablesIndex) { x.runes[0].chr = -1 }), false},
+		{"string out of bounds", withIndex(func(x *confusablesIndex) { x.homoglyphs[0].n = 100 }), false},
+		{"asymmetric", withIndex(func(x *confusablesIndex) { x.homoglyphs[0] = x.homoglyphs[1] }), false},
+	}
+
+	for _, c := range cases {
+		err := validateIndex(c.index)
+		if (err == nil) != c.valid {
+			t.Errorf("unexpected validation, case: %v, expected valid: %v, actual: %v\n", c.name, c.valid, err)
+		}
+	}
+}
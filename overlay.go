@@ -0,0 +1,174 @@
+package confusablehomoglyphs
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// Overlay adds confusables to or removes them from the confusables data of
+// a Checker, e.g. organisation-specific homoglyphs, see WithOverlay.
+type Overlay struct {
+	// Add makes sources confusable with their prototypes, in order.
+	Add []ConfusablePair `json:"add"`
+	// Remove lists characters which are not confusable with anything
+	// anymore, after the additions.
+	Remove []string `json:"remove"`
+}
+
+// ConfusablePair is a character confusable with a prototype, like the lines
+// of confusables.txt. Adding it merges the confusable classes of both
+// under the prototype of Prototype. As sequences are always prototypes, a
+// sequence Source is swapped with a single character Prototype, and a pair
+// of two sequences is ignored.
+type ConfusablePair struct {
+	Source    string `json:"source"`
+	Prototype string `json:"prototype"`
+}
+
+// WithOverlay applies overlay on top of the confusables data of the
+// Checker, for every lookup: confusables, prototypes and script confusables.
+// Several overlays are applied in order. Empty or invalid UTF-8 strings are
+// ignored. Overlays changing nothing leave the data as is, the others
+// rebuild it, so such a Checker is best built once and reused.
+func WithOverlay(overlay Overlay) Option {
+	return func(c *Checker) {
+		c.overlays = append(c.overlays, overlay)
+	}
+}
+
+// withOverlay returns a copy of d with overlay applied, d itself if the
+// overlay changes nothing. The entries of the classes it does not change
+// keep their homoglyphs in the order of d.
+func (d *confusables) withOverlay(overlay Overlay) *confusables {
+	if len(overlay.Add) == 0 && len(overlay.Remove) == 0 {
+		return d
+	}
+
+	prototypes, classes := d.prototypeMaps()
+	// touched are the prototypes of the classes changed by the overlay,
+	// before and after the change
+	touched := map[string]bool{}
+
+	prototypeOf := func(str string) string {
+		if chr, size := utf8.DecodeRuneInString(str); size == len(str) {
+			if p, ok := prototypes[chr]; ok {
+				return p
+			}
+		}
+		return str
+	}
+	move := func(from, to string) {
+		touched[from], touched[to] = true, true
+		for _, m := range append([]string{from}, classes[from]...) {
+			chr, _ := utf8.DecodeRuneInString(m)
+			prototypes[chr] = to
+			classes[to] = append(classes[to], m)
+		}
+		delete(classes, from)
+	}
+
+	for _, pair := range overlay.Add {
+		if !validOverlayString(pair.Source) || !validOverlayString(pair.Prototype) {
+			continue
+		}
+		source, prototype := prototypeOf(pair.Source), prototypeOf(pair.Prototype)
+		if isSequence(source) && !isSequence(prototype) {
+			source, prototype = prototype, source
+		}
+		if source == prototype || isSequence(source) {
+			continue
+		}
+		move(source, prototype)
+	}
+
+	for _, str := range overlay.Remove {
+		if !validOverlayString(str) {
+			continue
+		}
+		if chr, size := utf8.DecodeRuneInString(str); size == len(str) {
+			if p, ok := prototypes[chr]; ok {
+				touched[p] = true
+				delete(prototypes, chr)
+				classes[p] = removeString(classes[p], str)
+				continue
+			}
+		}
+		// the lowest member of the class takes over as prototype
+		members := classes[str]
+		if len(members) == 0 {
+			continue
+		}
+		sort.Strings(members)
+		touched[str], touched[members[0]] = true, true
+		delete(classes, str)
+		chr, _ := utf8.DecodeRuneInString(members[0])
+		delete(prototypes, chr)
+		for _, m := range members[1:] {
+			chr, _ := utf8.DecodeRuneInString(m)
+			prototypes[chr] = members[0]
+			classes[members[0]] = append(classes[members[0]], m)
+		}
+	}
+
+	if len(touched) == 0 {
+		return d
+	}
+
+	// the current data, and the names of its characters
+	m := map[string][]Homoglyph{}
+	names := map[string]string{}
+	d.index.forEach(func(key string, homoglyphs []homoglyphRef) {
+		m[key] = d.index.materialize(homoglyphs)
+		for _, h := range m[key] {
+			names[h.C] = h.N
+		}
+	})
+
+	// rebuild the symmetric data of the touched classes: the prototype lists
+	// all characters of its class, which only list their prototype
+	for p := range touched {
+		delete(m, p)
+		for _, chr := range d.members(p) {
+			delete(m, chr)
+		}
+	}
+	for p := range touched {
+		c := classes[p]
+		if len(c) == 0 {
+			delete(classes, p)
+			continue
+		}
+		sort.Strings(c)
+		for _, chr := range c {
+			m[p] = append(m[p], Homoglyph{C: chr, N: names[chr]})
+			m[chr] = []Homoglyph{{C: p, N: names[p]}}
+		}
+	}
+	for p, c := range classes {
+		if len(c) == 0 {
+			delete(classes, p)
+		}
+	}
+
+	data := &confusables{index: buildIndex(m)}
+	data.setClasses(prototypes, classes)
+	data.buildSequences()
+	return data
+}
+
+func validOverlayString(str string) bool {
+	return str != "" && utf8.ValidString(str)
+}
+
+func isSequence(str string) bool {
+	return utf8.RuneCountInString(str) > 1
+}
+
+func removeString(strs []string, str string) []string {
+	for i, s := range strs {
+		if s == str {
+			return append(strs[:i], strs[i+1:]...)
+		}
+	}
+	return strs
+}
@@ -0,0 +1,143 @@
+package confusablehomoglyphs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithOverlay(t *testing.T) {
+	checker := NewChecker(WithOverlay(Overlay{
+		Add: []ConfusablePair{
+			{Source: "ʘ", Prototype: "O"},
+			{Source: "vv", Prototype: "w"},
+			{Source: "", Prototype: "o"},
+		},
+		Remove: []string{"|", "а", "l"},
+	}))
+
+	cases := []struct {
+		str      string
+		skeleton string
+	}{
+		{"ʘK", "OK"},
+		{"⊙K", "OK"},
+		{"w", "vv"},
+		{"vv", "vv"},
+		{"|", "|"},
+		{"а", "а"},
+		// l is removed, the lowest character of its class is the prototype
+		{"l", "l"},
+		{"I", "1"},
+		{"Il", "1l"},
+	}
+
+	for _, c := range cases {
+		skeleton := checker.MapPrototypes(c.str)
+		if skeleton != c.skeleton {
+			t.Errorf("unexpected skeleton, string: %v, expected: %q, actual: %q\n", c.str, c.skeleton, skeleton)
+		}
+	}
+
+	if skeleton := checker.Skeleton("ʘ\u0301K"); skeleton != "O\u0301K" {
+		t.Errorf("unexpected skeleton, string: %+q, expected: %+q, actual: %+q\n", "ʘ\u0301K", "O\u0301K", skeleton)
+	}
+
+	if ok, _ := checker.AreConfusable("ʘK", "OK"); !ok {
+		t.Errorf("unexpected AreConfusable, expected: %v, actual: %v\n", true, ok)
+	}
+	if ok, _ := AreConfusable("ʘK", "OK"); ok {
+		t.Errorf("unexpected AreConfusable without overlay, expected: %v, actual: %v\n", false, ok)
+	}
+	if err := validateIndex(checker.data.index); err != nil {
+		t.Errorf("unexpected invalid data: %v\n", err)
+	}
+}
+
+func TestWithOverlayConfusables(t *testing.T) {
+	cases := []struct {
+		overlay Overlay
+		str     string
+		count   int
+	}{
+		{Overlay{}, "pаypal", 1},
+		{Overlay{Remove: []string{"а"}}, "pаypal", 0},
+		// а is still confusable with ɑ
+		{Overlay{Remove: []string{"a"}}, "pаypal", 1},
+		{Overlay{Remove: []string{"a", "ɑ"}}, "pаypal", 0},
+		{Overlay{}, "жuk", 0},
+		{Overlay{Add: []ConfusablePair{{Source: "ж", Prototype: "x"}}}, "жuk", 1},
+	}
+
+	for _, c := range cases {
+		checker := NewChecker(WithPreferredAliases("latin"), WithOverlay(c.overlay))
+		count := len(checker.IsConfusable(c.str))
+		if count != c.count {
+			t.Errorf("unexpected confusables count, overlay: %v, string: %v, expected: %v, actual: %v\n", c.overlay, c.str, c.count, count)
+		}
+	}
+}
+
+func TestWithEmptyOverlay(t *testing.T) {
+	checker := NewChecker(WithOverlay(Overlay{}))
+	d := loadedConfusables()
+	for chr := rune(0); chr < 0x20000; chr++ {
+		if checker.data.mapPrototypes(string(chr)) != d.mapPrototypes(string(chr)) {
+			t.Errorf("unexpected skeleton, character: %U, expected: %q, actual: %q\n", chr, d.mapPrototypes(string(chr)), checker.data.mapPrototypes(string(chr)))
+		}
+		if len(checker.data.class(chr)) != len(d.class(chr)) {
+			t.Errorf("unexpected class, character: %U, expected: %q, actual: %q\n", chr, d.class(chr), checker.data.class(chr))
+		}
+	}
+	if checker.data.index.len() != d.index.len() || len(checker.data.index.homoglyphs) != len(d.index.homoglyphs) {
+		t.Errorf("unexpected data size, expected: %v, actual: %v\n", d.index.len(), checker.data.index.len())
+	}
+}
+
+func TestWithOverlayResults(t *testing.T) {
+	base := NewChecker(WithPreferredScripts(ScriptLatin))
+	cases := []struct {
+		overlay Overlay
+		same    bool
+	}{
+		{Overlay{}, true},
+		// no change: invalid strings, pairs already confusable and
+		// characters which are not confusable
+		{Overlay{Add: []ConfusablePair{{Source: "\xff", Prototype: "a"}, {Source: "а", Prototype: "a"}}, Remove: []string{"ж"}}, true},
+		{Overlay{Add: []ConfusablePair{{Source: "ж", Prototype: "x"}}}, false},
+	}
+
+	for _, c := range cases {
+		checker := NewChecker(WithPreferredScripts(ScriptLatin), WithOverlay(c.overlay))
+		if same := checker.data == base.data; same != c.same {
+			t.Errorf("unexpected data, overlay: %v, expected same: %v, actual: %v\n", c.overlay, c.same, same)
+		}
+		// the results of untouched characters keep the order of the data
+		for _, corpus := range benchmarkCorpora {
+			for _, str := range corpus.strs {
+				expected := base.FindConfusables(str)
+				actual := checker.FindConfusables(str)
+				if !reflect.DeepEqual(actual, expected) {
+					t.Errorf("unexpected confusables, overlay: %v, string: %q, expected: %v, actual: %v\n", c.overlay, str, expected, actual)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkNewCheckerWithOverlay(b *testing.B) {
+	overlays := []struct {
+		name    string
+		overlay Overlay
+	}{
+		{"Empty", Overlay{}},
+		{"Add", Overlay{Add: []ConfusablePair{{Source: "ж", Prototype: "x"}}}},
+	}
+	for _, o := range overlays {
+		b.Run(o.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				NewChecker(WithOverlay(o.overlay))
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package confusablehomoglyphs
+
+// Restriction is a restriction level as defined in UTS #39 section 5.2.
+// Levels are ordered from the most to the least restrictive.
+type Restriction int
+
+const (
+	// ASCIIOnly strings only contain characters in the ASCII range.
+	ASCIIOnly Restriction = iota + 1
+	// SingleScript strings only contain characters from a single script,
+	// along with characters from Common and Inherited.
+	SingleScript
+	// HighlyRestrictive strings are single script, or only contain
+	// characters from Latin + Han + Hiragana + Katakana, Latin + Han +
+	// Bopomofo or Latin + Han + Hangul.
+	HighlyRestrictive
+	// ModeratelyRestrictive strings are highly restrictive, or only contain
+	// characters from Latin and one other recommended script except Cyrillic
+	// and Greek.
+	ModeratelyRestrictive
+	// MinimallyRestrictive strings may contain arbitrary mixtures of scripts.
+	MinimallyRestrictive
+	// Unrestricted strings contain characters which are never part of
+	// identifiers: unassigned, private use, control or format characters.
+	Unrestricted
+)
+
+func (r Restriction) String() string {
+	switch r {
+	case ASCIIOnly:
+		return "ASCII-Only"
+	case SingleScript:
+		return "Single Script"
+	case HighlyRestrictive:
+		return "Highly Restrictive"
+	case ModeratelyRestrictive:
+		return "Moderately Restrictive"
+	case MinimallyRestrictive:
+		return "Minimally Restrictive"
+	case Unrestricted:
+		return "Unrestricted"
+	}
+	return "Unknown"
+}
+
+// highlyRestrictiveScripts are the script combinations allowed in highly
+// restrictive strings, besides single scripts.
+var highlyRestrictiveScripts = []ScriptSet{
+	NewScriptSet(ScriptLatin, ScriptHan, ScriptHiragana, ScriptKatakana),
+	NewScriptSet(ScriptLatin, ScriptHan, ScriptBopomofo),
+	NewScriptSet(ScriptLatin, ScriptHan, ScriptHangul),
+}
+
+// recommendedScripts are the scripts recommended for use in identifiers,
+// see UAX #31 table 5.
+var recommendedScripts = NewScriptSet(
+	ScriptArabic, ScriptArmenian, ScriptBengali, ScriptBopomofo,
+	ScriptCyrillic, ScriptDevanagari, ScriptEthiopic, ScriptGeorgian,
+	ScriptGreek, ScriptGujarati, ScriptGurmukhi, ScriptHan,
+	ScriptHangul, ScriptHebrew, ScriptHiragana, ScriptKannada,
+	ScriptKatakana, ScriptKhmer, ScriptLao, ScriptLatin,
+	ScriptMalayalam, ScriptMyanmar, ScriptOriya, ScriptSinhala,
+	ScriptTamil, ScriptTelugu, ScriptThaana, ScriptThai,
+	ScriptTibetan,
+)
+
+// moderatelyRestrictiveScripts are the scripts allowed along with Latin in
+// moderately restrictive strings.
+var moderatelyRestrictiveScripts = func() ScriptSet {
+	set := recommendedScripts
+	set.Remove(ScriptCyrillic)
+	set.Remove(ScriptGreek)
+	set.Remove(ScriptLatin)
+	return set
+}()
+
+// RestrictionLevel returns the restriction level of str as defined in
+// UTS #39 section 5.2.
+// The identifier profile is approximated: strings containing unassigned,
+// private use, control or format characters are Unrestricted.
+func RestrictionLevel(str string) Restriction {
+	return defaultScripts().restrictionLevel(str)
+}
+
+func (s *scriptData) restrictionLevel(str string) Restriction {
+	isASCII := true
+	for _, chr := range str {
+		script, category := s.scriptCategory(chr)
+		if script == ScriptUnknown || category == CategoryCc || category == CategoryCf {
+			return Unrestricted
+		}
+		if chr > 0x7F {
+			isASCII = false
+		}
+	}
+	if isASCII {
+		return ASCIIOnly
+	}
+
+	if scripts, all := s.resolve(str, ScriptSet{}, universalScripts); all || scripts.Len() > 0 {
+		return SingleScript
+	}
+
+	// the levels are defined by the scripts covering str: every character
+	// has one of them in its augmented script extensions, see UTS #39
+	// section 5.1
+	sets := []ScriptSet{}
+	for _, chr := range str {
+		if set, universal := s.augmentedSet(chr, ScriptSet{}, universalScripts); !universal {
+			sets = append(sets, set)
+		}
+	}
+
+	for _, allowed := range highlyRestrictiveScripts {
+		if coveredBy(sets, allowed) {
+			return HighlyRestrictive
+		}
+	}
+
+	for _, other := range moderatelyRestrictiveScripts.Scripts() {
+		if coveredBy(sets, NewScriptSet(ScriptLatin, other)) {
+			return ModeratelyRestrictive
+		}
+	}
+
+	return MinimallyRestrictive
+}
+
+// coveredBy checks if scripts intersect each of the augmented script sets
+// of the characters of a string.
+func coveredBy(sets []ScriptSet, scripts ScriptSet) bool {
+	for _, set := range sets {
+		if set.Intersect(scripts).Len() == 0 {
+			return false
+		}
+	}
+	return true
+}
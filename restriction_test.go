@@ -0,0 +1,58 @@
+package confusablehomoglyphs
+
+import (
+	"testing"
+)
+
+func TestRestrictionLevel(t *testing.T) {
+	cases := []struct {
+		str   string
+		level Restriction
+	}{
+		{"paypal", ASCIIOnly},
+		{"B. C", ASCIIOnly},
+		{"Café", SingleScript},
+		{"ρτ.τ", SingleScript},
+		{"東京タワー", SingleScript},
+		{"Tokyo東京タワーへ", HighlyRestrictive},
+		{"Seoul서울", HighlyRestrictive},
+		{"Shalomשלום", ModeratelyRestrictive},
+		{"pаypal", MinimallyRestrictive},
+		{"Alloτ", MinimallyRestrictive},
+		{"שלוםсвет", MinimallyRestrictive},
+		{"pay\x00pal", Unrestricted},
+		{"pay\u200bpal", Unrestricted},
+		{"\ue000", Unrestricted},
+	}
+
+	for _, c := range cases {
+		level := RestrictionLevel(c.str)
+		if level != c.level {
+			t.Errorf("unexpected restriction level, string: %v, expected: %v, actual: %v\n", c.str, c.level, level)
+		}
+	}
+}
+
+func TestRestrictionLevelExtensions(t *testing.T) {
+	s := withExtensions(defaultScripts(), testExtensions)
+	cases := []struct {
+		str   string
+		level Restriction
+	}{
+		{"東京タワー", SingleScript},
+		{"Tokyoー", HighlyRestrictive},
+		{"abc\u060c", ModeratelyRestrictive},
+		{"abc\u0964", ModeratelyRestrictive},
+		{"abc\u0640", ModeratelyRestrictive},
+		{"abc\u3001", HighlyRestrictive},
+		{"abc\u0483", MinimallyRestrictive},
+		{"Alloτ\u060c", MinimallyRestrictive},
+	}
+
+	for _, c := range cases {
+		level := s.restrictionLevel(c.str)
+		if level != c.level {
+			t.Errorf("unexpected restriction level, string: %v, expected: %v, actual: %v\n", c.str, c.level, level)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package confusablehomoglyphs
+
+// IsWholeScriptConfusable checks if the single-script str could be mistaken
+// for a string written in targetScript, see Checker.IsWholeScriptConfusable.
+// It is false if targetScript is not a known script alias.
+func IsWholeScriptConfusable(str string, targetScript string) bool {
+	target, err := ParseScript(targetScript)
+	if err != nil {
+		return false
+	}
+	return NewChecker().IsWholeScriptConfusable(str, target)
+}
+
+// IsMixedScriptConfusable checks if the mixed-script str could be mistaken
+// for a string written in its dominant script,
+// see Checker.IsMixedScriptConfusable.
+func IsMixedScriptConfusable(str string) bool {
+	return NewChecker().IsMixedScriptConfusable(str)
+}
+
+// IsWholeScriptConfusable checks if the single-script str could be mistaken
+// for a string written in target, e.g. the Cyrillic "асе" for the
+// Latin "ace": every character of str which is not Common or Inherited
+// is confusable with a character from target.
+// See UTS #39 section 4.
+func (c *Checker) IsWholeScriptConfusable(str string, target Script) bool {
+	scripts, all := c.scripts.resolve(str, ScriptSet{}, universalScripts)
+	if all || scripts.Len() == 0 || scripts.Has(target) {
+		return false
+	}
+
+	for _, chr := range str {
+		if _, all := c.scripts.augmentedSet(chr, ScriptSet{}, universalScripts); all {
+			continue
+		}
+		if !c.data.confusableInScript(c.scripts, chr, target) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsMixedScriptConfusable checks if the mixed-script str could be mistaken
+// for a string written in its dominant script, the script most of its
+// characters belong to: all characters from the other scripts, which are not
+// Common or Inherited, are confusable with characters from the dominant
+// script. E.g. "pаypal" mixes Latin with a Cyrillic "а" confusable with "a",
+// but "Tokyo東京" is not confusable.
+// See UTS #39 section 4.
+func (c *Checker) IsMixedScriptConfusable(str string) bool {
+	chars := []rune{}
+	sets := []ScriptSet{}
+	counts := [len(scriptNames)]int{}
+	for _, chr := range str {
+		set, all := c.scripts.augmentedSet(chr, ScriptSet{}, universalScripts)
+		if all {
+			continue
+		}
+		chars = append(chars, chr)
+		sets = append(sets, set)
+		for _, s := range set.Scripts() {
+			counts[s]++
+		}
+	}
+
+	max := 0
+	for _, count := range counts {
+		if count > max {
+			max = count
+		}
+	}
+	if max == len(chars) {
+		// single-script
+		return false
+	}
+
+	for dominant, count := range counts {
+		if count != max {
+			continue
+		}
+		if c.data.minorityConfusableIn(c.scripts, chars, sets, Script(dominant)) {
+			return true
+		}
+	}
+	return false
+}
+
+// minorityConfusableIn checks if all chars which do not belong to script are
+// confusable with characters from script, sets being the augmented script
+// sets of chars.
+func (d *confusables) minorityConfusableIn(s *scriptData, chars []rune, sets []ScriptSet, script Script) bool {
+	for i, chr := range chars {
+		if sets[i].Has(script) {
+			continue
+		}
+		if !d.confusableInScript(s, chr, script) {
+			return false
+		}
+	}
+	return true
+}
+
+// confusableInScript checks if chr is confusable with a character or
+// sequence from script.
+func (d *confusables) confusableInScript(s *scriptData, chr rune, script Script) bool {
+	for _, c := range d.class(chr) {
+		if c == string(chr) {
+			continue
+		}
+		scripts, all := s.resolve(c, ScriptSet{}, universalScripts)
+		if !all && scripts.Has(script) {
+			return true
+		}
+	}
+	return false
+}
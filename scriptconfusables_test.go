@@ -0,0 +1,55 @@
+package confusablehomoglyphs
+
+import (
+	"testing"
+)
+
+func TestIsWholeScriptConfusable(t *testing.T) {
+	cases := []struct {
+		str          string
+		targetScript string
+		isConfusable bool
+	}{
+		{"асе", "latin", true},
+		{"ѕсоре", "latin", true},
+		{"асе-1", "latin", true},
+		{"асе", "cyrillic", false},
+		{"паук", "latin", false},
+		{"ace", "latin", false},
+		{"ace", "cyrillic", true},
+		{"aсe", "latin", false},
+		{"1-2", "latin", false},
+		{"", "latin", false},
+	}
+
+	for _, c := range cases {
+		isConfusable := IsWholeScriptConfusable(c.str, c.targetScript)
+		if isConfusable != c.isConfusable {
+			t.Errorf("unexpected isWholeScriptConfusable, string: %v, target: %v, expected: %v, actual: %v\n", c.str, c.targetScript, c.isConfusable, isConfusable)
+		}
+	}
+}
+
+func TestIsMixedScriptConfusable(t *testing.T) {
+	cases := []struct {
+		str          string
+		isConfusable bool
+	}{
+		{"pаypal", true},
+		{"ΑlaskaJazz", true},
+		{"раураl", true},
+		{"paypal", false},
+		{"Tokyo東京", false},
+		{"ひらがなカタカナ漢字", false},
+		{"Bonjour Привет", false},
+		{"AlloΓ", false},
+		{"", false},
+	}
+
+	for _, c := range cases {
+		isConfusable := IsMixedScriptConfusable(c.str)
+		if isConfusable != c.isConfusable {
+			t.Errorf("unexpected isMixedScriptConfusable, string: %v, expected: %v, actual: %v\n", c.str, c.isConfusable, isConfusable)
+		}
+	}
+}
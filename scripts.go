@@ -0,0 +1,193 @@
+package confusablehomoglyphs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Script is a script, or a writing system using several scripts like
+// ScriptJapanese, see the Script constants.
+type Script uint8
+
+func (s Script) String() string {
+	if int(s) < len(scriptNames) {
+		return scriptNames[s]
+	}
+	return scriptNames[ScriptUnknown]
+}
+
+// ParseScript returns the script with the alias name, case-insensitively,
+// e.g. "latin".
+func ParseScript(name string) (Script, error) {
+	upper := strings.ToUpper(name)
+	for s, n := range scriptNames {
+		if Script(s) != ScriptUnknown && n == upper {
+			return Script(s), nil
+		}
+	}
+	return ScriptUnknown, fmt.Errorf("confusablehomoglyphs: unknown script %q", name)
+}
+
+// augmentedScripts are the writing systems added to the script set of
+// characters from scripts they use, see UTS #39 section 5.1.
+var augmentedScripts = map[Script][]Script{
+	ScriptHan:      {ScriptHanWithBopomofo, ScriptJapanese, ScriptKorean},
+	ScriptHiragana: {ScriptJapanese},
+	ScriptKatakana: {ScriptJapanese},
+	ScriptHangul:   {ScriptKorean},
+	ScriptBopomofo: {ScriptHanWithBopomofo},
+}
+
+// augmentedSets are the augmented script sets of the scripts: the script
+// along with the writing systems using it.
+var augmentedSets = func() (sets [len(scriptNames)]ScriptSet) {
+	for s := range sets {
+		sets[s].Add(Script(s))
+		for _, augmented := range augmentedScripts[Script(s)] {
+			sets[s].Add(augmented)
+		}
+	}
+	return sets
+}()
+
+// universalScripts are the scripts of characters used with every script.
+var universalScripts = NewScriptSet(ScriptCommon, ScriptInherited)
+
+// ScriptExtensionsOf returns the scripts chr is used with.
+// It is the script of chr unless chr is shared by several scripts, like
+// U+30FC KATAKANA-HIRAGANA PROLONGED SOUND MARK.
+func ScriptExtensionsOf(chr rune) []Script {
+	return defaultScripts().scriptExtensions(chr)
+}
+
+// ScriptExtensions returns the aliases of the scripts chr is used with,
+// see ScriptExtensionsOf.
+func ScriptExtensions(chr rune) []string {
+	return scriptsAliases(ScriptExtensionsOf(chr))
+}
+
+// ResolvedScriptsOf returns the resolved script set of str as defined in
+// UTS #39 section 5.1, i.e. the scripts all characters of str can belong to,
+// including the ScriptJapanese, ScriptKorean and ScriptHanWithBopomofo
+// writing systems. all is true if str only contains Common and Inherited
+// characters, which belong to every script. str is single-script if its
+// resolved script set is not empty.
+func ResolvedScriptsOf(str string) (scripts []Script, all bool) {
+	set, all := defaultScripts().resolve(str, ScriptSet{}, universalScripts)
+	return set.Scripts(), all
+}
+
+// ResolvedScripts returns the aliases of the resolved script set of str,
+// see ResolvedScriptsOf.
+func ResolvedScripts(str string) (scripts []string, all bool) {
+	resolved, all := ResolvedScriptsOf(str)
+	scripts = scriptsAliases(resolved)
+	sort.Strings(scripts)
+	return scripts, all
+}
+
+// resolve intersects the augmented script sets of the characters of
+// str. Characters from skippedScripts are ignored, characters from
+// universalScripts are ignored unless they have script extensions.
+// It returns true if no character restricted the set.
+func (s *scriptData) resolve(str string, skippedScripts ScriptSet, universalScripts ScriptSet) (ScriptSet, bool) {
+	var resolved ScriptSet
+	restricted := false
+	for i := 0; i < len(str); {
+		var set ScriptSet
+		var all bool
+		if str[i] < utf8.RuneSelf {
+			scripts, n := s.asciiScripts(str[i:])
+			set, all = scriptsAugmentedSet(scripts, skippedScripts, universalScripts)
+			i += n
+		} else {
+			chr, size := utf8.DecodeRuneInString(str[i:])
+			set, all = s.augmentedSet(chr, skippedScripts, universalScripts)
+			i += size
+		}
+		if all {
+			continue
+		}
+
+		if !restricted {
+			resolved, restricted = set, true
+			continue
+		}
+		resolved = resolved.Intersect(set)
+	}
+
+	return resolved, !restricted
+}
+
+// isMixedScript checks if the resolved script set of str, ignoring the
+// characters from allowedScripts, is empty.
+func (s *scriptData) isMixedScript(str string, allowedScripts ScriptSet) bool {
+	scripts, all := s.resolve(str, allowedScripts, ScriptSet{})
+	return !all && scripts.Len() == 0
+}
+
+// augmentedSet returns the augmented script set of chr, the scripts it is
+// used with along with the writing systems using them. It returns true if
+// chr is ignored as per resolve.
+func (s *scriptData) augmentedSet(chr rune, skippedScripts ScriptSet, universalScripts ScriptSet) (ScriptSet, bool) {
+	p := s.properties(chr)
+	if skippedScripts.Has(p.script) {
+		return ScriptSet{}, true
+	}
+
+	if p.extensions < 0 {
+		if universalScripts.Has(p.script) {
+			return ScriptSet{}, true
+		}
+		return augmentedSets[p.script], false
+	}
+
+	var set ScriptSet
+	for _, extension := range s.extensionsSets[p.extensions] {
+		set = set.Union(augmentedSets[extension])
+	}
+	return set, false
+}
+
+// scriptExtensions returns a copy of the script extensions of chr, or its
+// script if it has none.
+func (s *scriptData) scriptExtensions(chr rune) []Script {
+	if extensions := s.extensions(chr); extensions != nil {
+		return append([]Script(nil), extensions...)
+	}
+	return []Script{s.scriptOf(chr)}
+}
+
+// extensions returns the script extensions of chr,
+// or nil if chr only belongs to its own script. The returned slice is shared
+// and must not be modified.
+func (s *scriptData) extensions(chr rune) []Script {
+	i := s.properties(chr).extensions
+	if i < 0 {
+		return nil
+	}
+	set := s.extensionsSets[i]
+	return set[:len(set):len(set)]
+}
+
+// aliasesSet returns the set of scripts from their aliases. Unknown aliases
+// are ignored.
+func aliasesSet(aliases []string) ScriptSet {
+	var set ScriptSet
+	for _, a := range aliases {
+		if s, err := ParseScript(a); err == nil {
+			set.Add(s)
+		}
+	}
+	return set
+}
+
+func scriptsAliases(scripts []Script) []string {
+	aliases := make([]string, len(scripts))
+	for i, s := range scripts {
+		aliases[i] = s.String()
+	}
+	return aliases
+}
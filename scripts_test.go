@@ -0,0 +1,172 @@
+package confusablehomoglyphs
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestParseScript(t *testing.T) {
+	cases := []struct {
+		name   string
+		script Script
+		err    bool
+	}{
+		{"LATIN", ScriptLatin, false},
+		{"latin", ScriptLatin, false},
+		{"Canadian_Aboriginal", ScriptCanadianAboriginal, false},
+		{"japanese", ScriptJapanese, false},
+		{"latn", ScriptUnknown, true},
+		{"Unknown", ScriptUnknown, true},
+	}
+
+	for _, c := range cases {
+		script, err := ParseScript(c.name)
+		if script != c.script || (err != nil) != c.err {
+			t.Errorf("unexpected script, name: %v, expected: %v %v, actual: %v %v\n", c.name, c.script, c.err, script, err)
+		}
+	}
+}
+
+func TestScriptString(t *testing.T) {
+	cases := []struct {
+		script Script
+		name   string
+	}{
+		{ScriptLatin, "LATIN"},
+		{ScriptOldSogdian, "OLD_SOGDIAN"},
+		{ScriptKorean, "KOREAN"},
+		{ScriptUnknown, "Unknown"},
+		{Script(255), "Unknown"},
+	}
+
+	for _, c := range cases {
+		if name := c.script.String(); name != c.name {
+			t.Errorf("unexpected script name, expected: %v, actual: %v\n", c.name, name)
+		}
+	}
+}
+
+// testExtensions are script extensions of Unicode 14.0.0 for the scripts of
+// Unicode 11.0.0, given to the compiled data by withExtensions as the 11.0.0
+// dataset has none.
+var testExtensions = map[rune][]Script{
+	0x0483: {ScriptCyrillic, ScriptOldPermic},
+	0x060C: {ScriptArabic, ScriptSyriac, ScriptThaana, ScriptNko, ScriptHanifiRohingya},
+	0x0640: {ScriptArabic, ScriptSyriac, ScriptMandaic, ScriptManichaean, ScriptPsalterPahlavi, ScriptAdlam, ScriptHanifiRohingya, ScriptSogdian},
+	0x064F: {ScriptArabic, ScriptSyriac},
+	0x0660: {ScriptArabic, ScriptThaana},
+	0x0661: {ScriptArabic, ScriptThaana},
+	0x0662: {ScriptArabic, ScriptThaana},
+	0x0964: {ScriptDevanagari, ScriptBengali, ScriptGurmukhi, ScriptGujarati, ScriptOriya, ScriptTamil, ScriptTelugu, ScriptKannada, ScriptMalayalam, ScriptSinhala, ScriptSylotiNagri, ScriptTakri, ScriptGrantha, ScriptMahajani, ScriptKhudawadi, ScriptTirhuta, ScriptMasaramGondi, ScriptDogra, ScriptGunjalaGondi},
+	0x3001: {ScriptHangul, ScriptHiragana, ScriptKatakana, ScriptBopomofo, ScriptHan, ScriptYi},
+	0x30FC: {ScriptHiragana, ScriptKatakana},
+}
+
+// withExtensions returns a copy of s where the code points of extensions
+// have these script extensions.
+func withExtensions(s *scriptData, extensions map[rune][]Script) *scriptData {
+	c := *s
+	c.extensionsSets = append([][]Script{}, s.extensionsSets...)
+	c.trie.blocks = append([]uint16{}, s.trie.blocks...)
+	c.trie.values = append([]uint16{}, s.trie.values...)
+	c.trie.properties = append([]codePointProperties{}, s.trie.properties...)
+	for chr, scripts := range extensions {
+		p := c.properties(chr)
+		p.extensions = int16(len(c.extensionsSets))
+		c.extensionsSets = append(c.extensionsSets, scripts)
+
+		// blocks are shared, chr gets a copy of its own
+		block := int(c.trie.blocks[chr>>trieBlockBits]) << trieBlockBits
+		c.trie.blocks[chr>>trieBlockBits] = uint16(len(c.trie.values) >> trieBlockBits)
+		c.trie.values = append(c.trie.values, c.trie.values[block:block+1<<trieBlockBits]...)
+		c.trie.values[len(c.trie.values)-1<<trieBlockBits+int(chr)&(1<<trieBlockBits-1)] = uint16(len(c.trie.properties))
+		c.trie.properties = append(c.trie.properties, p)
+	}
+	return &c
+}
+
+func TestScriptExtensions(t *testing.T) {
+	cases := []struct {
+		char    rune
+		aliases []string
+	}{
+		{'A', []string{"LATIN"}},
+		{'-', []string{"COMMON"}},
+	}
+
+	for _, c := range cases {
+		aliases := ScriptExtensions(c.char)
+		if !reflect.DeepEqual(aliases, c.aliases) {
+			t.Errorf("unexpected script extensions, char: %c, expected: %v, actual: %v\n", c.char, c.aliases, aliases)
+		}
+	}
+
+	s := withExtensions(defaultScripts(), testExtensions)
+	extensionsCases := []struct {
+		char    rune
+		aliases []string
+	}{
+		{'A', []string{"LATIN"}},
+		{'ー', []string{"HIRAGANA", "KATAKANA"}},
+		{'٠', []string{"ARABIC", "THAANA"}},
+		{'ヘ', []string{"KATAKANA"}},
+	}
+
+	for _, c := range extensionsCases {
+		aliases := scriptsAliases(s.scriptExtensions(c.char))
+		if !reflect.DeepEqual(aliases, c.aliases) {
+			t.Errorf("unexpected script extensions, char: %c, expected: %v, actual: %v\n", c.char, c.aliases, aliases)
+		}
+	}
+}
+
+func TestResolvedScripts(t *testing.T) {
+	cases := []struct {
+		str     string
+		scripts []string
+		all     bool
+	}{
+		{"", []string{}, true},
+		{"1.-", []string{}, true},
+		{"Abc", []string{"LATIN"}, false},
+		{"ひらがなカタカナ漢字", []string{"JAPANESE"}, false},
+		{"漢字", []string{"HAN", "HAN_WITH_BOPOMOFO", "JAPANESE", "KOREAN"}, false},
+		{"한국어漢字", []string{"KOREAN"}, false},
+		{"Aα", []string{}, false},
+	}
+
+	for _, c := range cases {
+		scripts, all := ResolvedScripts(c.str)
+		if !reflect.DeepEqual(scripts, c.scripts) || all != c.all {
+			t.Errorf("unexpected resolved scripts, string: %v, expected: %v %v, actual: %v %v\n", c.str, c.scripts, c.all, scripts, all)
+		}
+	}
+
+	s := withExtensions(defaultScripts(), testExtensions)
+	extensionsCases := []struct {
+		str     string
+		scripts []string
+		all     bool
+	}{
+		{"ー", []string{"HIRAGANA", "JAPANESE", "KATAKANA"}, false},
+		{"カタカナー", []string{"JAPANESE", "KATAKANA"}, false},
+		{"٠١٢", []string{"ARABIC", "THAANA"}, false},
+	}
+
+	for _, c := range extensionsCases {
+		set, all := s.resolve(c.str, ScriptSet{}, universalScripts)
+		scripts := scriptsAliases(set.Scripts())
+		sort.Strings(scripts)
+		if !reflect.DeepEqual(scripts, c.scripts) || all != c.all {
+			t.Errorf("unexpected resolved scripts, string: %v, expected: %v %v, actual: %v %v\n", c.str, c.scripts, c.all, scripts, all)
+		}
+	}
+}
+
+func TestResolvedScriptsOf(t *testing.T) {
+	scripts, all := ResolvedScriptsOf("ひらがなカタカナ漢字")
+	if !reflect.DeepEqual(scripts, []Script{ScriptJapanese}) || all {
+		t.Errorf("unexpected resolved scripts, expected: %v, actual: %v %v\n", []Script{ScriptJapanese}, scripts, all)
+	}
+}
@@ -0,0 +1,89 @@
+package confusablehomoglyphs
+
+import "math/bits"
+
+// scriptSetWords is the number of words of a ScriptSet, one bit per script.
+const scriptSetWords = (len(scriptNames) + 63) / 64
+
+// ScriptSet is a set of scripts stored as a bitset. It is a value: copying
+// it copies the set, and using it does not allocate. The zero value is the
+// empty set.
+type ScriptSet [scriptSetWords]uint64
+
+// NewScriptSet returns the set of scripts. Unknown scripts are ignored.
+func NewScriptSet(scripts ...Script) ScriptSet {
+	var set ScriptSet
+	for _, s := range scripts {
+		set.Add(s)
+	}
+	return set
+}
+
+// Add adds script to the set. Unknown scripts are ignored.
+func (set *ScriptSet) Add(script Script) {
+	if int(script) < len(scriptNames) {
+		set[script/64] |= 1 << (script % 64)
+	}
+}
+
+// Remove removes script from the set.
+func (set *ScriptSet) Remove(script Script) {
+	if int(script) < len(scriptNames) {
+		set[script/64] &^= 1 << (script % 64)
+	}
+}
+
+// Has checks if script belongs to the set.
+func (set ScriptSet) Has(script Script) bool {
+	return int(script) < len(scriptNames) && set[script/64]&(1<<(script%64)) != 0
+}
+
+// Len returns the number of scripts of the set.
+func (set ScriptSet) Len() int {
+	n := 0
+	for _, w := range set {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Intersect returns the scripts belonging to both set and other.
+func (set ScriptSet) Intersect(other ScriptSet) ScriptSet {
+	for i := range set {
+		set[i] &= other[i]
+	}
+	return set
+}
+
+// Union returns the scripts belonging to set or other.
+func (set ScriptSet) Union(other ScriptSet) ScriptSet {
+	for i := range set {
+		set[i] |= other[i]
+	}
+	return set
+}
+
+// Scripts returns the scripts of the set, in the order of the Script
+// constants.
+func (set ScriptSet) Scripts() []Script {
+	scripts := make([]Script, 0, set.Len())
+	for i, w := range set {
+		for w != 0 {
+			scripts = append(scripts, Script(i*64+bits.TrailingZeros64(w)))
+			w &= w - 1
+		}
+	}
+	return scripts
+}
+
+// AppendAliases appends the aliases of the scripts of the set to dst, in the
+// order of the Script constants, and returns the extended slice.
+func (set ScriptSet) AppendAliases(dst []string) []string {
+	for i, w := range set {
+		for w != 0 {
+			dst = append(dst, Script(i*64+bits.TrailingZeros64(w)).String())
+			w &= w - 1
+		}
+	}
+	return dst
+}
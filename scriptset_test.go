@@ -0,0 +1,86 @@
+package confusablehomoglyphs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScriptSet(t *testing.T) {
+	set := NewScriptSet(ScriptLatin, ScriptKorean, ScriptGreek, Script(255))
+	set.Add(ScriptLatin)
+	set.Remove(ScriptGreek)
+
+	expected := []Script{ScriptLatin, ScriptKorean}
+	if scripts := set.Scripts(); !reflect.DeepEqual(scripts, expected) {
+		t.Errorf("unexpected scripts, expected: %v, actual: %v\n", expected, scripts)
+	}
+	if set.Len() != 2 {
+		t.Errorf("unexpected length, expected: %v, actual: %v\n", 2, set.Len())
+	}
+	if !set.Has(ScriptKorean) || set.Has(ScriptGreek) || set.Has(Script(255)) {
+		t.Errorf("unexpected membership, set: %v\n", set.Scripts())
+	}
+
+	aliases := set.AppendAliases([]string{"COMMON"})
+	if !reflect.DeepEqual(aliases, []string{"COMMON", "LATIN", "KOREAN"}) {
+		t.Errorf("unexpected aliases, expected: %v, actual: %v\n", []string{"COMMON", "LATIN", "KOREAN"}, aliases)
+	}
+}
+
+func TestScriptSetOperations(t *testing.T) {
+	a := NewScriptSet(ScriptLatin, ScriptHan)
+	b := NewScriptSet(ScriptHan, ScriptJapanese)
+
+	cases := []struct {
+		name     string
+		set      ScriptSet
+		expected []Script
+	}{
+		{"intersect", a.Intersect(b), []Script{ScriptHan}},
+		{"union", a.Union(b), []Script{ScriptLatin, ScriptHan, ScriptJapanese}},
+		{"empty", ScriptSet{}, []Script{}},
+	}
+
+	for _, c := range cases {
+		if scripts := c.set.Scripts(); !reflect.DeepEqual(scripts, c.expected) {
+			t.Errorf("unexpected scripts, case: %v, expected: %v, actual: %v\n", c.name, c.expected, scripts)
+		}
+	}
+}
+
+func TestUniqueScriptSet(t *testing.T) {
+	cases := []struct {
+		str     string
+		scripts []Script
+	}{
+		{"ABC", []Script{ScriptLatin}},
+		{"ρAτ-", []Script{ScriptCommon, ScriptLatin, ScriptGreek}},
+		{"", []Script{}},
+	}
+
+	for _, c := range cases {
+		if scripts := UniqueScriptSet(c.str).Scripts(); !reflect.DeepEqual(scripts, c.scripts) {
+			t.Errorf("unexpected unique scripts, expected: %v, actual: %v\n", c.scripts, scripts)
+		}
+	}
+}
+
+func TestScriptSetAllocations(t *testing.T) {
+	checker := NewChecker()
+	aliases := make([]string, 0, 4)
+	cases := []struct {
+		name string
+		f    func()
+	}{
+		{"IsMixedScript", func() { IsMixedScript("Abç-ρ東京カタカナ", nil) }},
+		{"Checker.IsMixedScript", func() { checker.IsMixedScript("Abç-ρ東京カタカナ") }},
+		{"UniqueScriptSet", func() { aliases = UniqueScriptSet("Abç-ρ東京").AppendAliases(aliases[:0]) }},
+		{"ScriptCategory", func() { ScriptCategory('ρ') }},
+	}
+
+	for _, c := range cases {
+		if allocs := testing.AllocsPerRun(100, c.f); allocs != 0 {
+			t.Errorf("unexpected allocations, case: %v, expected: %v, actual: %v\n", c.name, 0, allocs)
+		}
+	}
+}
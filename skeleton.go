@@ -0,0 +1,273 @@
+package confusablehomoglyphs
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ConfusableDifference is a position where two confusable strings use
+// different characters that look the same.
+type ConfusableDifference struct {
+	OffsetA   int    `json:"offset_a"`
+	OffsetB   int    `json:"offset_b"`
+	A         string `json:"a"`
+	B         string `json:"b"`
+	Prototype string `json:"prototype"`
+}
+
+// Skeleton returns the skeleton of str defined in UTS #39 section 4: str in
+// NFD with every character replaced by the prototype of its confusable
+// class, in NFD again. Strings with the same skeleton are confusable, e.g.
+// "pаypal" with a Cyrillic а and "paypal", or precomposed "é" and
+// "e\u0301". The normalization tables are those of golang.org/x/text, which
+// decompose the characters of the confusables data as their Unicode version
+// does since decompositions are stable across versions.
+func Skeleton(str string) string {
+	return loadedConfusables().skeleton(str)
+}
+
+func (d *confusables) skeleton(str string) string {
+	return norm.NFD.String(d.mapPrototypes(norm.NFD.String(str)))
+}
+
+// MapPrototypes replaces every character of str by the prototype of its
+// confusable class. It is the mapping step of Skeleton, without the NFD
+// applied before and after it, e.g. precomposed "é" and "e\u0301" are
+// mapped to different strings.
+func MapPrototypes(str string) string {
+	return loadedConfusables().mapPrototypes(str)
+}
+
+func (d *confusables) mapPrototypes(str string) string {
+	var b strings.Builder
+	b.Grow(len(str))
+	for _, chr := range str {
+		if p, ok := d.prototype(chr); ok {
+			b.WriteString(p)
+			continue
+		}
+		b.WriteRune(chr)
+	}
+	return b.String()
+}
+
+// AreConfusable checks if the distinct strings a and b render alike, i.e.
+// they have the same Skeleton. It also returns the differences between a
+// and b aligned on their skeletons, in order of appearance, with the
+// skeleton they share as Prototype. Identical strings are not considered
+// confusable.
+func AreConfusable(a, b string) (bool, []ConfusableDifference) {
+	return loadedConfusables().areConfusable(a, b)
+}
+
+func (d *confusables) areConfusable(a, b string) (bool, []ConfusableDifference) {
+	if a == b || d.skeleton(a) != d.skeleton(b) {
+		return false, nil
+	}
+
+	segmentsA := d.prototypeSegments(a)
+	segmentsB := d.prototypeSegments(b)
+	if joinPrototypes(segmentsA) != joinPrototypes(segmentsB) {
+		// the canonical reordering of combining marks across segments
+		// leaves no finer alignment than the whole strings
+		return true, []ConfusableDifference{{A: a, B: b, Prototype: d.skeleton(a)}}
+	}
+
+	differences := []ConfusableDifference{}
+	// both mappings are equal, so walking the segments until the consumed
+	// mapping lengths meet gives the smallest aligned groups
+	i, j := 0, 0
+	endA, endB := 0, 0
+	for i < len(segmentsA) && j < len(segmentsB) {
+		startA, startB := segmentsA[i], segmentsB[j]
+		endA += len(segmentsA[i].prototype)
+		endB += len(segmentsB[j].prototype)
+		i++
+		j++
+		for endA != endB {
+			if endA < endB {
+				endA += len(segmentsA[i].prototype)
+				i++
+			} else {
+				endB += len(segmentsB[j].prototype)
+				j++
+			}
+		}
+
+		strA := a[startA.offset : segmentsA[i-1].offset+segmentsA[i-1].size]
+		strB := b[startB.offset : segmentsB[j-1].offset+segmentsB[j-1].size]
+		if strA != strB {
+			differences = append(differences, ConfusableDifference{
+				OffsetA:   startA.offset,
+				OffsetB:   startB.offset,
+				A:         strA,
+				B:         strB,
+				Prototype: d.skeleton(strA),
+			})
+		}
+	}
+
+	return true, differences
+}
+
+type prototypeSegment struct {
+	offset    int
+	size      int
+	prototype string
+}
+
+// prototypeSegments splits str into its normalization segments, a starter
+// followed by the combining marks which may be reordered with it, along
+// with their skeletons.
+func (d *confusables) prototypeSegments(str string) []prototypeSegment {
+	segments := []prototypeSegment{}
+	for offset := 0; offset < len(str); {
+		// invalid UTF-8 bytes are segments of their own, one byte long
+		// unlike the U+FFFD they are mapped to
+		size := norm.NFD.NextBoundaryInString(str[offset:], true)
+		segments = append(segments, prototypeSegment{
+			offset:    offset,
+			size:      size,
+			prototype: d.skeleton(str[offset : offset+size]),
+		})
+		offset += size
+	}
+	return segments
+}
+
+func joinPrototypes(segments []prototypeSegment) string {
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteString(s.prototype)
+	}
+	return b.String()
+}
+
+// prototypeKey gives the prototype of the confusable class of chr.
+type prototypeKey struct {
+	chr       rune
+	prototype string
+}
+
+// classKey gives the other characters of the confusable class of prototype
+// as the range from start to end of the class members.
+type classKey struct {
+	prototype  string
+	start, end uint32
+}
+
+// prototype returns the prototype of the confusable class of chr, if chr is
+// not a prototype itself.
+func (d *confusables) prototype(chr rune) (string, bool) {
+	i := sort.Search(len(d.prototypes), func(i int) bool {
+		return d.prototypes[i].chr >= chr
+	})
+	if i == len(d.prototypes) || d.prototypes[i].chr != chr {
+		return "", false
+	}
+	return d.prototypes[i].prototype, true
+}
+
+// members returns the other characters of the confusable class of the
+// prototype p.
+func (d *confusables) members(p string) []string {
+	i := sort.Search(len(d.classes), func(i int) bool {
+		return d.classes[i].prototype >= p
+	})
+	if i == len(d.classes) || d.classes[i].prototype != p {
+		return nil
+	}
+	k := d.classes[i]
+	return d.classMembers[k.start:k.end:k.end]
+}
+
+// class returns the prototype of chr followed by the other
+// characters of its confusable class.
+func (d *confusables) class(chr rune) []string {
+	p, ok := d.prototype(chr)
+	if !ok {
+		p = string(chr)
+	}
+	return append([]string{p}, d.members(p)...)
+}
+
+// buildPrototypes derives the prototype of every confusable class from the
+// confusables data. The data is symmetric: a prototype lists all characters
+// of its class while every other character only lists its prototype.
+// For classes with two members the multi-character one, or else the lower
+// code point, is taken as prototype.
+func (d *confusables) buildPrototypes() map[rune]string {
+	prototypes := map[rune]string{}
+	d.index.forEach(func(key string, homoglyphs []homoglyphRef) {
+		if len(homoglyphs) != 1 {
+			return
+		}
+		chr, size := utf8.DecodeRuneInString(key)
+		if size != len(key) {
+			// sequences are always prototypes
+			return
+		}
+		other := d.index.char(homoglyphs[0])
+		if others, _ := d.index.lookup(other); len(others) == 1 &&
+			utf8.RuneCountInString(other) == 1 && other > key {
+			return
+		}
+		prototypes[chr] = other
+	})
+	return prototypes
+}
+
+func buildClasses(prototypes map[rune]string) map[string][]string {
+	classes := map[string][]string{}
+	for chr, p := range prototypes {
+		classes[p] = append(classes[p], string(chr))
+	}
+	for _, c := range classes {
+		sort.Strings(c)
+	}
+	return classes
+}
+
+// setClasses sets the prototypes and the classes of d from prototypes and
+// classes, as built by buildPrototypes and buildClasses.
+func (d *confusables) setClasses(prototypes map[rune]string, classes map[string][]string) {
+	d.prototypes = make([]prototypeKey, 0, len(prototypes))
+	for chr, p := range prototypes {
+		d.prototypes = append(d.prototypes, prototypeKey{chr: chr, prototype: p})
+	}
+	sort.Slice(d.prototypes, func(i, j int) bool {
+		return d.prototypes[i].chr < d.prototypes[j].chr
+	})
+
+	keys := make([]string, 0, len(classes))
+	for p, c := range classes {
+		if len(c) > 0 {
+			keys = append(keys, p)
+		}
+	}
+	sort.Strings(keys)
+	d.classes = make([]classKey, 0, len(keys))
+	d.classMembers = make([]string, 0, len(prototypes))
+	for _, p := range keys {
+		start := uint32(len(d.classMembers))
+		d.classMembers = append(d.classMembers, classes[p]...)
+		d.classes = append(d.classes, classKey{prototype: p, start: start, end: uint32(len(d.classMembers))})
+	}
+}
+
+// prototypeMaps returns the prototypes and the classes of d as maps, as
+// built by buildPrototypes and buildClasses.
+func (d *confusables) prototypeMaps() (map[rune]string, map[string][]string) {
+	prototypes := make(map[rune]string, len(d.prototypes))
+	for _, k := range d.prototypes {
+		prototypes[k.chr] = k.prototype
+	}
+	classes := make(map[string][]string, len(d.classes))
+	for _, k := range d.classes {
+		classes[k.prototype] = append([]string{}, d.classMembers[k.start:k.end]...)
+	}
+	return prototypes, classes
+}
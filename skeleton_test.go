@@ -0,0 +1,135 @@
+package confusablehomoglyphs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapPrototypes(t *testing.T) {
+	cases := []struct {
+		str      string
+		skeleton string
+	}{
+		{"paypal", "paypal"},
+		{"pаypal", "paypal"},
+		{"ρaypal", "paypal"},
+		{"microsoft", "rnicrosoft"},
+		{"rnicrosoft", "rnicrosoft"},
+		{"Αlaska", "Alaska"},
+		{"ו", "l"},
+		{"", ""},
+	}
+
+	for _, c := range cases {
+		skeleton := MapPrototypes(c.str)
+		if skeleton != c.skeleton {
+			t.Errorf("unexpected skeleton, string: %v, expected: %q, actual: %q\n", c.str, c.skeleton, skeleton)
+		}
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	cases := []struct {
+		str      string
+		skeleton string
+	}{
+		{"paypal", "paypal"},
+		{"pаypal", "paypal"},
+		{"\u00e9", "e\u0301"},
+		{"e\u0301", "e\u0301"},
+		{"\u212b", "A\u030a"},
+		{"x\u0301\u0327", "x\u0326\u0301"},
+		{"ו", "l"},
+		{"", ""},
+	}
+
+	for _, c := range cases {
+		skeleton := Skeleton(c.str)
+		if skeleton != c.skeleton {
+			t.Errorf("unexpected skeleton, string: %+q, expected: %+q, actual: %+q\n", c.str, c.skeleton, skeleton)
+		}
+	}
+}
+
+func TestSkeletonEquality(t *testing.T) {
+	cases := []struct {
+		a         string
+		b         string
+		confusing bool
+	}{
+		{"paypal", "pаypal", true},
+		{"microsoft", "rnicrosoft", true},
+		{"paypal", "paypai", false},
+		{"֭", "֖", true},
+		{"\u00e9", "e\u0301", true},
+		{"caf\u00e9", "cаfe\u0301", true},
+		{"x\u0301\u0327", "x\u0327\u0301", true},
+		{"\u00e9", "e", false},
+	}
+
+	for _, c := range cases {
+		confusing := Skeleton(c.a) == Skeleton(c.b)
+		if confusing != c.confusing {
+			t.Errorf("unexpected skeleton equality, a: %+q, b: %+q, expected: %v, actual: %v\n", c.a, c.b, c.confusing, confusing)
+		}
+	}
+
+	// the prototypes alone do not normalize
+	if MapPrototypes("\u00e9") == MapPrototypes("e\u0301") {
+		t.Errorf("unexpected prototypes equality, a: %+q, b: %+q\n", "\u00e9", "e\u0301")
+	}
+}
+
+func TestAreConfusable(t *testing.T) {
+	cases := []struct {
+		a             string
+		b             string
+		areConfusable bool
+		differences   []ConfusableDifference
+	}{
+		{"paypal", "paypal", false, nil},
+		{"paypal", "paypai", false, nil},
+		{"paypal", "pаypal", true, []ConfusableDifference{
+			{OffsetA: 1, OffsetB: 1, A: "a", B: "а", Prototype: "a"},
+		}},
+		{"microsoft", "rnicrosоft", true, []ConfusableDifference{
+			{OffsetA: 0, OffsetB: 0, A: "m", B: "rn", Prototype: "rn"},
+			{OffsetA: 6, OffsetB: 7, A: "o", B: "о", Prototype: "o"},
+		}},
+		{"ραу", "pаy", true, []ConfusableDifference{
+			{OffsetA: 0, OffsetB: 0, A: "ρ", B: "p", Prototype: "p"},
+			{OffsetA: 2, OffsetB: 1, A: "α", B: "а", Prototype: "a"},
+			{OffsetA: 4, OffsetB: 3, A: "у", B: "y", Prototype: "y"},
+		}},
+		{"\xff", "\xfe", true, []ConfusableDifference{
+			{OffsetA: 0, OffsetB: 0, A: "\xff", B: "\xfe", Prototype: "\uFFFD"},
+		}},
+		{"a\xff", "a\uFFFD", true, []ConfusableDifference{
+			{OffsetA: 1, OffsetB: 1, A: "\xff", B: "\uFFFD", Prototype: "\uFFFD"},
+		}},
+		{"caf\u00e9", "cafe\u0301", true, []ConfusableDifference{
+			{OffsetA: 3, OffsetB: 3, A: "\u00e9", B: "e\u0301", Prototype: "e\u0301"},
+		}},
+		{"caf\u00e9", "cаfe\u0301", true, []ConfusableDifference{
+			{OffsetA: 1, OffsetB: 1, A: "a", B: "а", Prototype: "a"},
+			{OffsetA: 3, OffsetB: 4, A: "\u00e9", B: "e\u0301", Prototype: "e\u0301"},
+		}},
+		{"x\u0301\u0327y", "x\u0327\u0301y", true, []ConfusableDifference{
+			{OffsetA: 0, OffsetB: 0, A: "x\u0301\u0327", B: "x\u0327\u0301", Prototype: "x\u0326\u0301"},
+		}},
+		{"pa\xffypal", "pа\xfeypal", true, []ConfusableDifference{
+			{OffsetA: 1, OffsetB: 1, A: "a", B: "а", Prototype: "a"},
+			{OffsetA: 2, OffsetB: 3, A: "\xff", B: "\xfe", Prototype: "\uFFFD"},
+		}},
+	}
+
+	for _, c := range cases {
+		areConfusable, differences := AreConfusable(c.a, c.b)
+		if areConfusable != c.areConfusable {
+			t.Errorf("unexpected areConfusable, a: %v, b: %v, expected: %v, actual: %v\n", c.a, c.b, c.areConfusable, areConfusable)
+		}
+		if !reflect.DeepEqual(differences, c.differences) {
+			t.Errorf("unexpected differences, a: %v, b: %v, expected: %v, actual: %v\n", c.a, c.b, c.differences, differences)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+// Command datadiff compares two datasets of tools/data, e.g. before adding
+// a new Unicode version:
+//
+//	go run ./tools/datadiff 11.0.0 path/to/12.0.0
+//
+// It reports the added and removed scripts and categories, the added and
+// removed confusable pairs, and the code points which changed script or
+// category. Arguments are dataset directories or versions of tools/data.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const dataDir = "./tools/data"
+
+const maxCodePoint = 0x10FFFF
+
+type homoglyph struct {
+	C string `json:"c"`
+	N string `json:"n"`
+}
+
+type categoryData struct {
+	ISO15924aliases  []string `json:"iso_15924_aliases"`
+	Categories       []string `json:"categories"`
+	CodePointsRanges [][]int  `json:"code_points_ranges"`
+}
+
+type dataset struct {
+	dir         string
+	confusables map[string][]homoglyph
+	categories  categoryData
+}
+
+// pair is a pair of confusable strings, the lower one first.
+type pair struct {
+	a, b string
+}
+
+// property is the script and the general category of a code point.
+type property struct {
+	script, category string
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: datadiff old new")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	old, err := readDataset(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	next, err := readDataset(flag.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	printDiff(os.Stdout, diffDatasets(old, next))
+}
+
+func readDataset(arg string) (dataset, error) {
+	d := dataset{dir: arg}
+	if info, err := os.Stat(arg); err != nil || !info.IsDir() {
+		d.dir = filepath.Join(dataDir, arg)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(d.dir, "confusables.json"))
+	if err != nil {
+		return d, err
+	}
+	if err := json.Unmarshal(b, &d.confusables); err != nil {
+		return d, fmt.Errorf("%s: %v", d.dir, err)
+	}
+
+	b, err = ioutil.ReadFile(filepath.Join(d.dir, "categories.json"))
+	if err != nil {
+		return d, err
+	}
+	if err := json.Unmarshal(b, &d.categories); err != nil {
+		return d, fmt.Errorf("%s: %v", d.dir, err)
+	}
+	return d, nil
+}
+
+// dataDiff is the difference between two datasets.
+type dataDiff struct {
+	oldDir, nextDir                    string
+	addedScripts, removedScripts       []string
+	addedCategories, removedCategories []string
+	addedPairs, removedPairs           []pair
+	// names are the names of the characters of the pairs.
+	names   map[string]string
+	changes []propertyChange
+}
+
+func diffDatasets(old, next dataset) dataDiff {
+	d := dataDiff{oldDir: old.dir, nextDir: next.dir}
+	d.addedScripts, d.removedScripts = diffStrings(old.categories.ISO15924aliases, next.categories.ISO15924aliases)
+	d.addedCategories, d.removedCategories = diffStrings(old.categories.Categories, next.categories.Categories)
+
+	oldPairs, nextPairs := pairs(old.confusables), pairs(next.confusables)
+	d.addedPairs, d.removedPairs = diffPairs(oldPairs, nextPairs), diffPairs(nextPairs, oldPairs)
+	d.names = names(old.confusables, next.confusables)
+
+	d.changes = diffProperties(properties(old.categories), properties(next.categories))
+	return d
+}
+
+func printDiff(w io.Writer, d dataDiff) {
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", d.oldDir, d.nextDir)
+
+	fmt.Fprintf(w, "\nscripts: %d added, %d removed\n", len(d.addedScripts), len(d.removedScripts))
+	printStrings(w, "+", d.addedScripts)
+	printStrings(w, "-", d.removedScripts)
+
+	fmt.Fprintf(w, "\ncategories: %d added, %d removed\n", len(d.addedCategories), len(d.removedCategories))
+	printStrings(w, "+", d.addedCategories)
+	printStrings(w, "-", d.removedCategories)
+
+	fmt.Fprintf(w, "\nconfusable pairs: %d added, %d removed\n", len(d.addedPairs), len(d.removedPairs))
+	printPairs(w, "+", d.addedPairs, d.names)
+	printPairs(w, "-", d.removedPairs, d.names)
+
+	count := 0
+	for _, c := range d.changes {
+		count += int(c.last-c.first) + 1
+	}
+	fmt.Fprintf(w, "\ncode points changing script or category: %d\n", count)
+	for _, c := range d.changes {
+		fmt.Fprintf(w, "  %s  %s/%s -> %s/%s\n", codePointRange(c.first, c.last),
+			c.old.script, c.old.category, c.next.script, c.next.category)
+	}
+}
+
+func diffStrings(old, next []string) ([]string, []string) {
+	oldSet, nextSet := map[string]bool{}, map[string]bool{}
+	for _, s := range old {
+		oldSet[s] = true
+	}
+	for _, s := range next {
+		nextSet[s] = true
+	}
+
+	added, removed := []string{}, []string{}
+	for _, s := range next {
+		if !oldSet[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range old {
+		if !nextSet[s] {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+func printStrings(w io.Writer, prefix string, strs []string) {
+	for _, s := range strs {
+		fmt.Fprintf(w, "%s %s\n", prefix, s)
+	}
+}
+
+// trimLRM removes the LEFT-TO-RIGHT MARKs wrapped around right-to-left
+// characters in the data of the Python library, so that they compare equal
+// to the data converted from the Unicode files.
+func trimLRM(str string) string {
+	return strings.Trim(str, "\u200e")
+}
+
+func pairs(confusables map[string][]homoglyph) map[pair]bool {
+	set := map[pair]bool{}
+	for k, homoglyphs := range confusables {
+		for _, h := range homoglyphs {
+			a, b := trimLRM(k), trimLRM(h.C)
+			if a > b {
+				a, b = b, a
+			}
+			set[pair{a, b}] = true
+		}
+	}
+	return set
+}
+
+// diffPairs returns the pairs of b missing from a, in order.
+func diffPairs(a, b map[pair]bool) []pair {
+	missing := []pair{}
+	for p := range b {
+		if !a[p] {
+			missing = append(missing, p)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].a != missing[j].a {
+			return missing[i].a < missing[j].a
+		}
+		return missing[i].b < missing[j].b
+	})
+	return missing
+}
+
+func names(datasets ...map[string][]homoglyph) map[string]string {
+	names := map[string]string{}
+	for _, confusables := range datasets {
+		for _, homoglyphs := range confusables {
+			for _, h := range homoglyphs {
+				names[trimLRM(h.C)] = h.N
+			}
+		}
+	}
+	return names
+}
+
+func printPairs(w io.Writer, prefix string, pairs []pair, names map[string]string) {
+	for _, p := range pairs {
+		fmt.Fprintf(w, "%s %s ↔ %s\t%s ↔ %s\n", prefix, codePoints(p.a), codePoints(p.b), names[p.a], names[p.b])
+	}
+}
+
+// properties returns the script and the general category of every code
+// point, empty for unassigned code points.
+func properties(data categoryData) []property {
+	props := make([]property, maxCodePoint+1)
+	for _, r := range data.CodePointsRanges {
+		p := property{script: data.ISO15924aliases[r[2]], category: data.Categories[r[3]]}
+		for cp := r[0]; cp <= r[1] && cp <= maxCodePoint; cp++ {
+			props[cp] = p
+		}
+	}
+	return props
+}
+
+type propertyChange struct {
+	first, last rune
+	old, next   property
+}
+
+// diffProperties returns the ranges of code points whose properties
+// changed the same way.
+func diffProperties(old, next []property) []propertyChange {
+	changes := []propertyChange{}
+	for cp := range old {
+		if old[cp] == next[cp] {
+			continue
+		}
+		o, n := named(old[cp]), named(next[cp])
+		if last := len(changes) - 1; last >= 0 && changes[last].last == rune(cp-1) &&
+			changes[last].old == o && changes[last].next == n {
+			changes[last].last = rune(cp)
+			continue
+		}
+		changes = append(changes, propertyChange{first: rune(cp), last: rune(cp), old: o, next: n})
+	}
+	return changes
+}
+
+func named(p property) property {
+	if p == (property{}) {
+		return property{script: "UNASSIGNED", category: "Cn"}
+	}
+	return p
+}
+
+func codePoints(str string) string {
+	cps := []string{}
+	for _, r := range str {
+		cps = append(cps, fmt.Sprintf("U+%04X", r))
+	}
+	return strings.Join(cps, " ")
+}
+
+func codePointRange(first, last rune) string {
+	if first == last {
+		return fmt.Sprintf("%04X", first)
+	}
+	return fmt.Sprintf("%04X..%04X", first, last)
+}
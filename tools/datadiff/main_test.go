@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffDatasets(t *testing.T) {
+	base := dataset{
+		dir: "old",
+		confusables: map[string][]homoglyph{
+			"a": {{C: "а", N: "CYRILLIC SMALL LETTER A"}},
+			"а": {{C: "a", N: "LATIN SMALL LETTER A"}},
+		},
+		categories: categoryData{
+			ISO15924aliases:  []string{"LATIN", "CYRILLIC"},
+			Categories:       []string{"Ll"},
+			CodePointsRanges: [][]int{{0x61, 0x7A, 0, 0}, {0x430, 0x44F, 1, 0}},
+		},
+	}
+
+	cases := []struct {
+		name              string
+		next              dataset
+		addedScripts      []string
+		removedScripts    []string
+		addedCategories   []string
+		removedCategories []string
+		addedPairs        []pair
+		removedPairs      []pair
+		changes           []propertyChange
+	}{
+		{"same", base, []string{}, []string{}, []string{}, []string{}, []pair{}, []pair{}, []propertyChange{}},
+		{"added", dataset{
+			dir: "next",
+			confusables: map[string][]homoglyph{
+				"a":  {{C: "а", N: "CYRILLIC SMALL LETTER A"}},
+				"а":  {{C: "a", N: "LATIN SMALL LETTER A"}},
+				"o":  {{C: "ο", N: "GREEK SMALL LETTER OMICRON"}},
+				"ο":  {{C: "o", N: "LATIN SMALL LETTER O"}},
+				"rn": {{C: "m", N: "LATIN SMALL LETTER M"}},
+			},
+			categories: categoryData{
+				ISO15924aliases:  []string{"LATIN", "CYRILLIC", "GREEK"},
+				Categories:       []string{"Ll", "Lu"},
+				CodePointsRanges: [][]int{{0x41, 0x5A, 0, 1}, {0x61, 0x7A, 0, 0}, {0x3B1, 0x3C9, 2, 0}, {0x430, 0x44F, 1, 0}},
+			},
+		}, []string{"GREEK"}, []string{}, []string{"Lu"}, []string{},
+			[]pair{{"m", "rn"}, {"o", "ο"}}, []pair{},
+			[]propertyChange{
+				{0x41, 0x5A, property{"UNASSIGNED", "Cn"}, property{"LATIN", "Lu"}},
+				{0x3B1, 0x3C9, property{"UNASSIGNED", "Cn"}, property{"GREEK", "Ll"}},
+			}},
+		{"removed", dataset{
+			dir:         "next",
+			confusables: map[string][]homoglyph{},
+			categories: categoryData{
+				ISO15924aliases:  []string{"LATIN", "COMMON"},
+				Categories:       []string{"Lo"},
+				CodePointsRanges: [][]int{{0x61, 0x7A, 0, 0}},
+			},
+		}, []string{"COMMON"}, []string{"CYRILLIC"}, []string{"Lo"}, []string{"Ll"},
+			[]pair{}, []pair{{"a", "а"}},
+			[]propertyChange{
+				{0x61, 0x7A, property{"LATIN", "Ll"}, property{"LATIN", "Lo"}},
+				{0x430, 0x44F, property{"CYRILLIC", "Ll"}, property{"UNASSIGNED", "Cn"}},
+			}},
+		{"changed", dataset{
+			dir:         "next",
+			confusables: base.confusables,
+			categories: categoryData{
+				ISO15924aliases:  []string{"LATIN", "CYRILLIC"},
+				Categories:       []string{"Ll"},
+				CodePointsRanges: [][]int{{0x61, 0x7A, 0, 0}, {0x430, 0x43F, 1, 0}, {0x440, 0x44F, 0, 0}},
+			},
+		}, []string{}, []string{}, []string{}, []string{}, []pair{}, []pair{},
+			[]propertyChange{
+				{0x440, 0x44F, property{"CYRILLIC", "Ll"}, property{"LATIN", "Ll"}},
+			}},
+	}
+
+	for _, c := range cases {
+		d := diffDatasets(base, c.next)
+		if !reflect.DeepEqual(d.addedScripts, c.addedScripts) || !reflect.DeepEqual(d.removedScripts, c.removedScripts) {
+			t.Errorf("unexpected scripts, case: %v, expected: %v %v, actual: %v %v\n", c.name, c.addedScripts, c.removedScripts, d.addedScripts, d.removedScripts)
+		}
+		if !reflect.DeepEqual(d.addedCategories, c.addedCategories) || !reflect.DeepEqual(d.removedCategories, c.removedCategories) {
+			t.Errorf("unexpected categories, case: %v, expected: %v %v, actual: %v %v\n", c.name, c.addedCategories, c.removedCategories, d.addedCategories, d.removedCategories)
+		}
+		if !reflect.DeepEqual(d.addedPairs, c.addedPairs) || !reflect.DeepEqual(d.removedPairs, c.removedPairs) {
+			t.Errorf("unexpected pairs, case: %v, expected: %v %v, actual: %v %v\n", c.name, c.addedPairs, c.removedPairs, d.addedPairs, d.removedPairs)
+		}
+		if !reflect.DeepEqual(d.changes, c.changes) {
+			t.Errorf("unexpected changes, case: %v, expected: %v, actual: %v\n", c.name, c.changes, d.changes)
+		}
+	}
+}
+
+func TestPrintDiff(t *testing.T) {
+	d := dataDiff{
+		oldDir:          "old",
+		nextDir:         "next",
+		addedScripts:    []string{"GREEK"},
+		removedScripts:  []string{},
+		addedCategories: []string{}, removedCategories: []string{"Lt"},
+		addedPairs:   []pair{{"o", "ο"}},
+		removedPairs: []pair{},
+		names:        map[string]string{"o": "LATIN SMALL LETTER O", "ο": "GREEK SMALL LETTER OMICRON"},
+		changes: []propertyChange{
+			{0x41, 0x5A, property{"LATIN", "L"}, property{"LATIN", "Lu"}},
+			{0x3B1, 0x3B1, property{"UNASSIGNED", "Cn"}, property{"GREEK", "Ll"}},
+		},
+	}
+	expected := []string{
+		"--- old",
+		"+++ next",
+		"scripts: 1 added, 0 removed",
+		"+ GREEK",
+		"categories: 0 added, 1 removed",
+		"- Lt",
+		"confusable pairs: 1 added, 0 removed",
+		"+ U+006F ↔ U+03BF\tLATIN SMALL LETTER O ↔ GREEK SMALL LETTER OMICRON",
+		"code points changing script or category: 27",
+		"  0041..005A  LATIN/L -> LATIN/Lu",
+		"  03B1  UNASSIGNED/Cn -> GREEK/Ll",
+	}
+
+	var buf bytes.Buffer
+	printDiff(&buf, d)
+	lines := []string{}
+	for _, l := range strings.Split(buf.String(), "\n") {
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("unexpected output, expected: %q, actual: %q\n", expected, lines)
+	}
+}
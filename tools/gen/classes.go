@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// confusableClasses are the prototypes, the confusable classes and the
+// sequences of the confusables data, derived the way the package derives
+// them for data loaded at runtime.
+type confusableClasses struct {
+	Prototypes     []prototypeKey
+	Classes        []confusableKey
+	Members        []string
+	Sequences      []string
+	SequenceStarts []confusableKey
+}
+
+type prototypeKey struct {
+	Chr       string
+	Prototype string
+}
+
+// buildClasses derives the prototype of every confusable class as
+// buildPrototypes of the package does, the classes of the prototypes, and
+// the sequences by first character as buildSequences of the package does.
+func buildClasses(confusables map[string][]homoglyph) confusableClasses {
+	confusables = trimLRMs(confusables)
+	keys := make([]string, 0, len(confusables))
+	for k := range confusables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// a prototype lists all characters of its class while every other
+	// character only lists its prototype, for classes with two members the
+	// multi-character one, or else the lower code point, is the prototype
+	prototypes := map[rune]string{}
+	classes := map[string][]string{}
+	sequences := []string{}
+	for _, k := range keys {
+		chr, size := utf8.DecodeRuneInString(k)
+		if size != len(k) {
+			sequences = append(sequences, k)
+			continue
+		}
+		homoglyphs := confusables[k]
+		if len(homoglyphs) != 1 {
+			continue
+		}
+		other := homoglyphs[0].C
+		if len(confusables[other]) == 1 && utf8.RuneCountInString(other) == 1 && other > k {
+			continue
+		}
+		prototypes[chr] = other
+		classes[other] = append(classes[other], k)
+	}
+
+	x := confusableClasses{}
+	chrs := make([]int, 0, len(prototypes))
+	for chr := range prototypes {
+		chrs = append(chrs, int(chr))
+	}
+	sort.Ints(chrs)
+	for _, chr := range chrs {
+		x.Prototypes = append(x.Prototypes, prototypeKey{
+			Chr:       fmt.Sprintf("0x%04X", chr),
+			Prototype: strconv.QuoteToASCII(prototypes[rune(chr)]),
+		})
+	}
+
+	protos := make([]string, 0, len(classes))
+	for p := range classes {
+		protos = append(protos, p)
+	}
+	sort.Strings(protos)
+	members := []string{}
+	for _, p := range protos {
+		start := len(members)
+		for _, m := range classes[p] {
+			members = append(members, strconv.QuoteToASCII(m))
+		}
+		x.Classes = append(x.Classes, confusableKey{Key: strconv.QuoteToASCII(p), Start: start, End: len(members)})
+	}
+	x.Members = formatStrings(members)
+
+	sort.Slice(sequences, func(i, j int) bool {
+		chrA, _ := utf8.DecodeRuneInString(sequences[i])
+		chrB, _ := utf8.DecodeRuneInString(sequences[j])
+		if chrA != chrB {
+			return chrA < chrB
+		}
+		la, lb := utf8.RuneCountInString(sequences[i]), utf8.RuneCountInString(sequences[j])
+		if la != lb {
+			return la > lb
+		}
+		return sequences[i] < sequences[j]
+	})
+	quoted := make([]string, len(sequences))
+	for i, s := range sequences {
+		quoted[i] = strconv.QuoteToASCII(s)
+		chr, _ := utf8.DecodeRuneInString(s)
+		key := fmt.Sprintf("0x%04X", chr)
+		if n := len(x.SequenceStarts); n > 0 && x.SequenceStarts[n-1].Key == key {
+			x.SequenceStarts[n-1].End++
+			continue
+		}
+		x.SequenceStarts = append(x.SequenceStarts, confusableKey{Key: key, Start: i, End: i + 1})
+	}
+	x.Sequences = formatStrings(quoted)
+	return x
+}
+
+// formatStrings returns the quoted strs as lines of a slice literal.
+func formatStrings(strs []string) []string {
+	lines := []string{}
+	for start := 0; start < len(strs); start += 8 {
+		end := start + 8
+		if end > len(strs) {
+			end = len(strs)
+		}
+		lines = append(lines, strings.Join(strs[start:end], ", ")+",")
+	}
+	return lines
+}
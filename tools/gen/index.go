@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// confusablesIndex is the compact form of the confusables data, as
+// confusablesIndex of the package: single characters keyed by code point,
+// the other keys by string, and the characters and names of the homoglyphs
+// stored once in a pool of strings.
+type confusablesIndex struct {
+	Runes      []confusableKey
+	Keys       []confusableKey
+	Homoglyphs []string
+	Strings    []string
+	Ends       []string
+}
+
+// buildIndex builds the index of confusables the way buildIndex of the
+// package does, without the LEFT-TO-RIGHT MARKs around right-to-left
+// characters.
+func buildIndex(confusables map[string][]homoglyph) confusablesIndex {
+	confusables = trimLRMs(confusables)
+	keys := make([]string, 0, len(confusables))
+	for k := range confusables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	x := confusablesIndex{}
+	strs := []string{}
+	ends := []int{}
+	size := 0
+	ids := map[string]int{}
+	intern := func(str string) int {
+		id, ok := ids[str]
+		if !ok {
+			id = len(strs)
+			ids[str] = id
+			strs = append(strs, str)
+			size += len(str)
+			ends = append(ends, size)
+		}
+		return id
+	}
+
+	refs := []string{}
+	for _, k := range keys {
+		start := len(refs)
+		for _, h := range confusables[k] {
+			refs = append(refs, fmt.Sprintf("{%d, %d}", intern(h.C), intern(h.N)))
+		}
+		key := confusableKey{Start: start, End: len(refs)}
+		if chr, size := utf8.DecodeRuneInString(k); k != "" && size == len(k) && utf8.ValidString(k) {
+			key.Key = fmt.Sprintf("0x%04X", chr)
+			x.Runes = append(x.Runes, key)
+		} else {
+			key.Key = strconv.QuoteToASCII(k)
+			x.Keys = append(x.Keys, key)
+		}
+	}
+
+	for start := 0; start < len(refs); start += 8 {
+		end := start + 8
+		if end > len(refs) {
+			end = len(refs)
+		}
+		x.Homoglyphs = append(x.Homoglyphs, strings.Join(refs[start:end], ", ")+",")
+	}
+	for start := 0; start < len(strs); start += 8 {
+		end := start + 8
+		if end > len(strs) {
+			end = len(strs)
+		}
+		x.Strings = append(x.Strings, strconv.QuoteToASCII(strings.Join(strs[start:end], "")))
+	}
+	x.Ends = formatInts(ends)
+	return x
+}
+
+// trimLRMs returns confusables with the LEFT-TO-RIGHT MARKs removed from
+// its keys and the characters of their homoglyphs, like trimLRMs of the
+// package.
+func trimLRMs(confusables map[string][]homoglyph) map[string][]homoglyph {
+	trimmed := make(map[string][]homoglyph, len(confusables))
+	for k, homoglyphs := range confusables {
+		key := trimLRM(k)
+	HOMOGLYPHS:
+		for _, h := range homoglyphs {
+			h.C = trimLRM(h.C)
+			for _, other := range trimmed[key] {
+				if other.C == h.C {
+					continue HOMOGLYPHS
+				}
+			}
+			trimmed[key] = append(trimmed[key], h)
+		}
+	}
+	return trimmed
+}
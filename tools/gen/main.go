@@ -0,0 +1,518 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+type categoryData struct {
+	ISO15924aliases        []string `json:"iso_15924_aliases"`
+	Categories             []string `json:"categories"`
+	CodePointsRanges       [][]int  `json:"code_points_ranges"`
+	ScriptExtensions       [][]int  `json:"script_extensions,omitempty"`
+	ScriptExtensionsRanges [][]int  `json:"script_extensions_ranges,omitempty"`
+}
+
+type scriptExtensionsData struct {
+	CodePointsRanges []scriptExtensionsRange `json:"code_points_ranges"`
+}
+
+type scriptExtensionsRange struct {
+	First   int      `json:"first"`
+	Last    int      `json:"last"`
+	Scripts []string `json:"scripts"`
+}
+
+var (
+	ucdDir = flag.String("ucd", "", "directory with the Unicode data files to convert into tools/data/<version>/*.json first: "+
+		"confusables.txt, Scripts.txt, UnicodeData.txt, and optionally ScriptExtensions.txt with PropertyValueAliases.txt")
+	ucdVersion = flag.String("version", "", "Unicode version of the files of -ucd, read from their headers by default")
+)
+
+const dataDir = "./tools/data"
+
+// dataset is the data of a Unicode version, read from tools/data/<version>.
+type dataset struct {
+	Version     string
+	confusables map[string][]homoglyph
+	categories  categoryData
+	meta        metadata
+	files       []sourceFile
+}
+
+func main() {
+	flag.Parse()
+
+	datasets, err := readDatasets()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *ucdDir != "" {
+		var previous categoryData
+		if len(datasets) > 0 {
+			previous = datasets[len(datasets)-1].categories
+		}
+		if err := convertUCD(*ucdDir, *ucdVersion, previous); err != nil {
+			log.Fatal(err)
+		}
+		if datasets, err = readDatasets(); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if len(datasets) == 0 {
+		log.Fatalf("no data in %s", dataDir)
+	}
+
+	aliases, categories := typesOf(datasets)
+	if err := generateTypes(aliases, categories); err != nil {
+		log.Fatal(err)
+	}
+
+	// tables of removed datasets
+	stale, err := filepath.Glob("data_*_gen.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	for _, d := range datasets {
+		if err := generateData(d); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err := writeSource("data_gen.go", datasetsTemplate, datasets); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readDatasets reads the datasets of tools/data, oldest first.
+func readDatasets() ([]dataset, error) {
+	dirs, err := ioutil.ReadDir(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	datasets := []dataset{}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		d, err := readDataset(dir.Name())
+		if err != nil {
+			return nil, err
+		}
+		datasets = append(datasets, d)
+	}
+	sort.Slice(datasets, func(i, j int) bool {
+		return compareVersions(datasets[i].Version, datasets[j].Version) < 0
+	})
+	return datasets, nil
+}
+
+func readDataset(version string) (dataset, error) {
+	d := dataset{Version: version}
+	dir := filepath.Join(dataDir, version)
+
+	if err := readJSON(filepath.Join(dir, "confusables.json"), &d.confusables); err != nil {
+		return d, err
+	}
+	if err := readJSON(filepath.Join(dir, "categories.json"), &d.categories); err != nil {
+		return d, err
+	}
+	var scriptExtensions scriptExtensionsData
+	if err := readJSON(filepath.Join(dir, "script_extensions.json"), &scriptExtensions); err != nil {
+		return d, err
+	}
+	if err := readJSON(filepath.Join(dir, "metadata.json"), &d.meta); err != nil {
+		return d, err
+	}
+	if d.meta.UnicodeVersion != version {
+		return d, fmt.Errorf("%s: unexpected unicode version %s", dir, d.meta.UnicodeVersion)
+	}
+	if len(d.meta.Sources) == 0 && d.meta.Provenance == "" {
+		return d, fmt.Errorf("%s: no sources nor provenance in metadata.json", dir)
+	}
+
+	d.files = []sourceFile{}
+	for _, name := range []string{"confusables.json", "categories.json", "script_extensions.json", "metadata.json"} {
+		sum, err := checksum(filepath.Join(dir, name))
+		if err != nil {
+			return d, err
+		}
+		d.files = append(d.files, sourceFile{Name: name, SHA256: sum})
+	}
+
+	if err := mergeScriptExtensions(&d.categories, scriptExtensions); err != nil {
+		return d, err
+	}
+	return d, validateDataset(d)
+}
+
+// compareVersions compares the dot separated numeric versions a and b.
+func compareVersions(a, b string) int {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		na, errA := strconv.Atoi(partsA[i])
+		nb, errB := strconv.Atoi(partsB[i])
+		if errA != nil || errB != nil {
+			if c := strings.Compare(partsA[i], partsB[i]); c != 0 {
+				return c
+			}
+			continue
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(partsA) - len(partsB)
+}
+
+// typesOf returns the scripts and categories of all datasets, in the order
+// of the newest one followed by the ones it lacks.
+func typesOf(datasets []dataset) ([]string, []string) {
+	aliases, categories := []string{}, []string{}
+	seenAliases, seenCategories := map[string]bool{}, map[string]bool{}
+	for i := len(datasets) - 1; i >= 0; i-- {
+		for _, a := range datasets[i].categories.ISO15924aliases {
+			if !seenAliases[a] {
+				seenAliases[a] = true
+				aliases = append(aliases, a)
+			}
+		}
+		for _, c := range datasets[i].categories.Categories {
+			if !seenCategories[c] {
+				seenCategories[c] = true
+				categories = append(categories, c)
+			}
+		}
+	}
+	return aliases, categories
+}
+
+// identifier returns the suffix of the identifiers of the tables of
+// version, e.g. 11_0_0.
+func identifier(version string) string {
+	return strings.Replace(version, ".", "_", -1)
+}
+
+// mergeScriptExtensions adds the script extensions sets to the categories
+// data, referring to scripts by their index in iso_15924_aliases.
+func mergeScriptExtensions(data *categoryData, extensions scriptExtensionsData) error {
+	aliases := indexOf(data.ISO15924aliases)
+	sets := map[string]int{}
+	data.ScriptExtensions = [][]int{}
+	data.ScriptExtensionsRanges = [][]int{}
+	for _, r := range extensions.CodePointsRanges {
+		set := []int{}
+		for _, s := range r.Scripts {
+			i, ok := aliases[s]
+			if !ok {
+				return fmt.Errorf("unknown script %s in script extensions of %04X", s, r.First)
+			}
+			set = append(set, i)
+		}
+		sort.Ints(set)
+
+		key := fmt.Sprint(set)
+		if _, ok := sets[key]; !ok {
+			sets[key] = len(data.ScriptExtensions)
+			data.ScriptExtensions = append(data.ScriptExtensions, set)
+		}
+		data.ScriptExtensionsRanges = append(data.ScriptExtensionsRanges, []int{r.First, r.Last, sets[key]})
+	}
+	return nil
+}
+
+type confusableKey struct {
+	Key   string
+	Start int
+	End   int
+}
+
+type codePointRange struct {
+	First    string
+	Last     string
+	Script   string
+	Category string
+}
+
+type scriptExtensionsSetRange struct {
+	First string
+	Last  string
+	Set   int
+}
+
+// generateData writes the data as static tables: code points ranges sorted
+// by code point along with their lookup table, and the confusables index
+// and the tables derived from it with their keys sorted for binary search.
+func generateData(d dataset) error {
+	categories := d.categories
+
+	codePointRanges := []codePointRange{}
+	for _, r := range categories.CodePointsRanges {
+		codePointRanges = append(codePointRanges, codePointRange{
+			First:    fmt.Sprintf("0x%04X", r[0]),
+			Last:     fmt.Sprintf("0x%04X", r[1]),
+			Script:   scriptConstant(categories.ISO15924aliases[r[2]]),
+			Category: categoryConstant(categories.Categories[r[3]]),
+		})
+	}
+
+	scriptExtensionsSets := [][]string{}
+	for _, set := range categories.ScriptExtensions {
+		scripts := []string{}
+		for _, s := range set {
+			scripts = append(scripts, scriptConstant(categories.ISO15924aliases[s]))
+		}
+		scriptExtensionsSets = append(scriptExtensionsSets, scripts)
+	}
+
+	scriptExtensionsRanges := []scriptExtensionsSetRange{}
+	for _, r := range categories.ScriptExtensionsRanges {
+		scriptExtensionsRanges = append(scriptExtensionsRanges, scriptExtensionsSetRange{
+			First: fmt.Sprintf("0x%04X", r[0]),
+			Last:  fmt.Sprintf("0x%04X", r[1]),
+			Set:   r[2],
+		})
+	}
+
+	trie, err := buildTrie(categories)
+	if err != nil {
+		return fmt.Errorf("dataset %s: %v", d.Version, err)
+	}
+
+	return writeSource("data_"+identifier(d.Version)+"_gen.go", dataTemplate, struct {
+		Identifier             string
+		UnicodeVersion         string
+		Sources                []sourceFile
+		Provenance             string
+		Files                  []sourceFile
+		Index                  confusablesIndex
+		Classes                confusableClasses
+		CodePointRanges        []codePointRange
+		ScriptExtensionsSets   [][]string
+		ScriptExtensionsRanges []scriptExtensionsSetRange
+		Trie                   scriptTrie
+	}{
+		Identifier:             identifier(d.Version),
+		UnicodeVersion:         d.Version,
+		Sources:                d.meta.Sources,
+		Provenance:             d.meta.Provenance,
+		Files:                  d.files,
+		Index:                  buildIndex(d.confusables),
+		Classes:                buildClasses(d.confusables),
+		CodePointRanges:        codePointRanges,
+		ScriptExtensionsSets:   scriptExtensionsSets,
+		ScriptExtensionsRanges: scriptExtensionsRanges,
+		Trie:                   trie,
+	})
+}
+
+func readJSON(path string, v interface{}) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func checksum(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// writeSource executes tmpl with data and writes the formatted result to path.
+func writeSource(path string, tmpl *template.Template, data interface{}) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, src, 0644)
+}
+
+var dataTemplate = template.Must(template.New("").Parse(`// Code generated by go generate; DO NOT EDIT.
+package confusablehomoglyphs
+
+var dataset_{{ .Identifier }} = &Dataset{
+	info: DataInfo{
+		UnicodeVersion: {{ printf "%q" .UnicodeVersion }},
+		Sources: []SourceFile{
+		{{- range .Sources }}
+			{Name: {{ printf "%q" .Name }}, Version: {{ printf "%q" .Version }}, Date: {{ printf "%q" .Date }}, SHA256: {{ printf "%q" .SHA256 }}},
+		{{- end }}
+		},
+		{{- if .Provenance }}
+		Provenance: {{ printf "%q" .Provenance }},
+		{{- end }}
+		Files: []SourceFile{
+		{{- range .Files }}
+			{Name: {{ printf "%q" .Name }}, SHA256: {{ printf "%q" .SHA256 }}},
+		{{- end }}
+		},
+	},
+	scripts: &scriptData{
+		ranges:           codePointRanges_{{ .Identifier }},
+		extensionsRanges: scriptExtensionsRanges_{{ .Identifier }},
+		extensionsSets:   scriptExtensionsSets_{{ .Identifier }},
+		trie: scriptTrie{
+			blocks:     scriptTrieBlocks_{{ .Identifier }},
+			values:     scriptTrieValues_{{ .Identifier }},
+			properties: codePointProperties_{{ .Identifier }},
+		},
+	},
+	data: &confusables{
+		index: confusablesIndex{
+			runes:      confusableRunes_{{ .Identifier }},
+			keys:       confusableKeys_{{ .Identifier }},
+			homoglyphs: confusableHomoglyphs_{{ .Identifier }},
+			strs: stringPool{
+				data: confusableStrings_{{ .Identifier }},
+				ends: confusableStringEnds_{{ .Identifier }},
+			},
+		},
+		prototypes:     confusablePrototypes_{{ .Identifier }},
+		classes:        confusableClasses_{{ .Identifier }},
+		classMembers:   confusableClassMembers_{{ .Identifier }},
+		sequences:      confusableSequences_{{ .Identifier }},
+		sequenceStarts: confusableSequenceStarts_{{ .Identifier }},
+	},
+}
+
+var codePointRanges_{{ .Identifier }} = []codePointRange{
+{{- range .CodePointRanges }}
+	{ {{- .First }}, {{ .Last }}, {{ .Script }}, {{ .Category -}} },
+{{- end }}
+}
+
+var scriptExtensionsSets_{{ .Identifier }} = [][]Script{
+{{- range .ScriptExtensionsSets }}
+	{ {{- range $i, $s := . }}{{ if $i }}, {{ end }}{{ $s }}{{ end -}} },
+{{- end }}
+}
+
+var scriptExtensionsRanges_{{ .Identifier }} = []scriptExtensionsRange{
+{{- range .ScriptExtensionsRanges }}
+	{ {{- .First }}, {{ .Last }}, {{ .Set -}} },
+{{- end }}
+}
+
+var scriptTrieBlocks_{{ .Identifier }} = []uint16{
+{{- range .Trie.Blocks }}
+	{{ . }}
+{{- end }}
+}
+
+var scriptTrieValues_{{ .Identifier }} = []uint16{
+{{- range .Trie.Values }}
+	{{ . }}
+{{- end }}
+}
+
+var codePointProperties_{{ .Identifier }} = []codePointProperties{
+{{- range .Trie.Properties }}
+	{ {{- .Script }}, {{ .Category }}, {{ .Extensions -}} },
+{{- end }}
+}
+
+var confusableRunes_{{ .Identifier }} = []runeKey{
+{{- range .Index.Runes }}
+	{ {{- .Key }}, {{ .Start }}, {{ .End -}} },
+{{- end }}
+}
+
+var confusableKeys_{{ .Identifier }} = []confusableKey{
+{{- range .Index.Keys }}
+	{ {{- .Key }}, {{ .Start }}, {{ .End -}} },
+{{- end }}
+}
+
+var confusableHomoglyphs_{{ .Identifier }} = []homoglyphRef{
+{{- range .Index.Homoglyphs }}
+	{{ . }}
+{{- end }}
+}
+
+const confusableStrings_{{ .Identifier }} = "" +
+{{- range $i, $s := .Index.Strings }}{{ if $i }} +{{ end }}
+	{{ $s }}
+{{- end }}
+
+var confusableStringEnds_{{ .Identifier }} = []uint32{
+{{- range .Index.Ends }}
+	{{ . }}
+{{- end }}
+}
+
+var confusablePrototypes_{{ .Identifier }} = []prototypeKey{
+{{- range .Classes.Prototypes }}
+	{ {{- .Chr }}, {{ .Prototype -}} },
+{{- end }}
+}
+
+var confusableClasses_{{ .Identifier }} = []classKey{
+{{- range .Classes.Classes }}
+	{ {{- .Key }}, {{ .Start }}, {{ .End -}} },
+{{- end }}
+}
+
+var confusableClassMembers_{{ .Identifier }} = []string{
+{{- range .Classes.Members }}
+	{{ . }}
+{{- end }}
+}
+
+var confusableSequences_{{ .Identifier }} = []string{
+{{- range .Classes.Sequences }}
+	{{ . }}
+{{- end }}
+}
+
+var confusableSequenceStarts_{{ .Identifier }} = []runeKey{
+{{- range .Classes.SequenceStarts }}
+	{ {{- .Key }}, {{ .Start }}, {{ .End -}} },
+{{- end }}
+}
+`))
+
+var datasetsTemplate = template.Must(template.New("").Funcs(template.FuncMap{"identifier": identifier}).Parse(`// Code generated by go generate; DO NOT EDIT.
+package confusablehomoglyphs
+
+// datasets are the datasets compiled into the package, oldest first.
+var datasets = []*Dataset{
+{{- range . }}
+	dataset_{{ identifier .Version }},
+{{- end }}
+}
+`))
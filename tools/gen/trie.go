@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// trieBlockBits is the number of low bits of the code points indexing
+// their block, as trieBlockBits of the package.
+const trieBlockBits = 7
+
+const maxRune = 0x10FFFF
+
+type codePointProperties struct {
+	Script     string
+	Category   string
+	Extensions int
+}
+
+// scriptTrie is the two-stage lookup table of the properties of the code
+// points: blocks maps the high bits of a code point to the start of its
+// block in values, which maps code points to their index in properties.
+// Identical blocks are shared.
+type scriptTrie struct {
+	Blocks     []string
+	Values     []string
+	Properties []codePointProperties
+}
+
+// buildTrie builds the lookup table of the script, category and script
+// extensions of every code point, ScriptUnknown and CategoryUnknown for the
+// unassigned ones.
+func buildTrie(categories categoryData) (scriptTrie, error) {
+	unknown := codePointProperties{Script: "ScriptUnknown", Category: "CategoryUnknown", Extensions: -1}
+	properties := []codePointProperties{unknown}
+	propertiesIndex := map[codePointProperties]int{unknown: 0}
+
+	values := make([]int, maxRune+1)
+	extensions := make([]int, maxRune+1)
+	for i := range extensions {
+		extensions[i] = -1
+	}
+	for _, r := range categories.ScriptExtensionsRanges {
+		for chr := r[0]; chr <= r[1]; chr++ {
+			extensions[chr] = r[2]
+		}
+	}
+	for _, r := range categories.CodePointsRanges {
+		if r[0] < 0 || r[1] > maxRune {
+			return scriptTrie{}, fmt.Errorf("code points range %04X-%04X out of bounds", r[0], r[1])
+		}
+		for chr := r[0]; chr <= r[1]; chr++ {
+			p := codePointProperties{
+				Script:     scriptConstant(categories.ISO15924aliases[r[2]]),
+				Category:   categoryConstant(categories.Categories[r[3]]),
+				Extensions: extensions[chr],
+			}
+			i, ok := propertiesIndex[p]
+			if !ok {
+				i = len(properties)
+				propertiesIndex[p] = i
+				properties = append(properties, p)
+			}
+			values[chr] = i
+		}
+	}
+
+	const blockSize = 1 << trieBlockBits
+	blocks := []int{}
+	blocksIndex := map[string]int{}
+	trieValues := []int{}
+	for start := 0; start <= maxRune; start += blockSize {
+		block := values[start : start+blockSize]
+		key := fmt.Sprint(block)
+		i, ok := blocksIndex[key]
+		if !ok {
+			i = len(trieValues) >> trieBlockBits
+			blocksIndex[key] = i
+			trieValues = append(trieValues, block...)
+		}
+		blocks = append(blocks, i)
+	}
+	if len(blocksIndex) > 1<<16 || len(properties) > 1<<16 {
+		return scriptTrie{}, fmt.Errorf("too many blocks or properties for the lookup table")
+	}
+
+	return scriptTrie{
+		Blocks:     formatInts(blocks),
+		Values:     formatInts(trieValues),
+		Properties: properties,
+	}, nil
+}
+
+// formatInts formats ints as lines of 16 comma separated values.
+func formatInts(ints []int) []string {
+	lines := []string{}
+	for start := 0; start < len(ints); start += 16 {
+		end := start + 16
+		if end > len(ints) {
+			end = len(ints)
+		}
+		line := make([]string, end-start)
+		for i, v := range ints[start:end] {
+			line[i] = fmt.Sprint(v)
+		}
+		lines = append(lines, strings.Join(line, ", ")+",")
+	}
+	return lines
+}
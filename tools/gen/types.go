@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"text/template"
+)
+
+// augmentedScripts are the writing systems made of several scripts,
+// see UTS #39 section 5.1.
+var augmentedScripts = []string{"HAN_WITH_BOPOMOFO", "JAPANESE", "KOREAN"}
+
+// majorCategories are the major classes of the general categories, the
+// first letter of their names.
+var majorCategories = []string{"L", "M", "N", "P", "S", "Z", "C"}
+
+type constant struct {
+	Name  string
+	Value string
+}
+
+// generateTypes writes the Script and GeneralCategory constants, in the
+// order of the categories data followed by the augmented scripts and the
+// major classes.
+func generateTypes(aliases []string, categories []string) error {
+	scripts := []constant{}
+	for _, a := range append(aliases, augmentedScripts...) {
+		scripts = append(scripts, constant{Name: scriptConstant(a), Value: a})
+	}
+
+	generalCategories := []constant{}
+	seen := map[string]bool{}
+	for _, c := range append(categories, majorCategories...) {
+		if !seen[c] {
+			seen[c] = true
+			generalCategories = append(generalCategories, constant{Name: categoryConstant(c), Value: c})
+		}
+	}
+
+	return writeSource("types_gen.go", typesTemplate, struct {
+		Scripts           []constant
+		GeneralCategories []constant
+	}{
+		Scripts:           scripts,
+		GeneralCategories: generalCategories,
+	})
+}
+
+// scriptConstant returns the name of the Script constant for alias,
+// e.g. ScriptCanadianAboriginal for CANADIAN_ABORIGINAL.
+func scriptConstant(alias string) string {
+	words := strings.Split(strings.ToLower(alias), "_")
+	for i, w := range words {
+		words[i] = strings.Title(w)
+	}
+	return "Script" + strings.Join(words, "")
+}
+
+// categoryConstant returns the name of the GeneralCategory constant for
+// category, e.g. CategoryPd.
+func categoryConstant(category string) string {
+	return "Category" + category
+}
+
+var typesTemplate = template.Must(template.New("").Parse(`// Code generated by go generate; DO NOT EDIT.
+package confusablehomoglyphs
+
+// Scripts, identified by their aliases.
+const (
+	ScriptUnknown Script = iota
+{{- range .Scripts }}
+	{{ .Name }}
+{{- end }}
+)
+
+var scriptNames = [...]string{
+	ScriptUnknown: "Unknown",
+{{- range .Scripts }}
+	{{ .Name }}: "{{ .Value }}",
+{{- end }}
+}
+
+// General categories, identified by their short names.
+const (
+	CategoryUnknown GeneralCategory = iota
+{{- range .GeneralCategories }}
+	{{ .Name }}
+{{- end }}
+)
+
+var categoryNames = [...]string{
+	CategoryUnknown: "Zzzz",
+{{- range .GeneralCategories }}
+	{{ .Name }}: "{{ .Value }}",
+{{- end }}
+}
+`))
@@ -0,0 +1,500 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const maxCodePoint = 0x10FFFF
+
+type homoglyph struct {
+	C string `json:"c"`
+	N string `json:"n"`
+}
+
+// metadata describes where the JSON files of a dataset come from.
+// Provenance explains how they were obtained when they were not converted
+// from the Unicode files listed in Sources.
+type metadata struct {
+	UnicodeVersion string       `json:"unicode_version"`
+	Sources        []sourceFile `json:"sources"`
+	Provenance     string       `json:"provenance,omitempty"`
+}
+
+type sourceFile struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Date    string `json:"date,omitempty"`
+	SHA256  string `json:"sha256"`
+}
+
+// convertUCD converts the Unicode Character Database files in dir into the
+// JSON files of tools/data/<version>, in the format inherited from the Python
+// library. The version is read from the headers of the files unless given.
+// Scripts and categories keep the order of previous, the categories data of
+// the newest dataset, new ones are appended.
+func convertUCD(dir string, version string, previous categoryData) error {
+	names, categories, err := parseUnicodeData(filepath.Join(dir, "UnicodeData.txt"))
+	if err != nil {
+		return err
+	}
+
+	scripts, err := parseScripts(filepath.Join(dir, "Scripts.txt"))
+	if err != nil {
+		return err
+	}
+
+	confusables, err := parseConfusables(filepath.Join(dir, "confusables.txt"), names)
+	if err != nil {
+		return err
+	}
+
+	data := buildCategoryData(scripts, categories, previous)
+
+	sourceNames := []string{"confusables.txt", "Scripts.txt", "UnicodeData.txt"}
+	extensions := scriptExtensionsData{CodePointsRanges: []scriptExtensionsRange{}}
+	scriptExtensionsPath := filepath.Join(dir, "ScriptExtensions.txt")
+	if _, err := os.Stat(scriptExtensionsPath); err == nil {
+		sourceNames = append(sourceNames, "ScriptExtensions.txt", "PropertyValueAliases.txt")
+		aliases, err := parseScriptAliases(filepath.Join(dir, "PropertyValueAliases.txt"))
+		if err != nil {
+			return err
+		}
+		extensions, err = parseScriptExtensions(scriptExtensionsPath, aliases, data.ISO15924aliases)
+		if err != nil {
+			return err
+		}
+	}
+
+	meta := metadata{Sources: []sourceFile{}}
+	for _, name := range sourceNames {
+		source, err := readSourceFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		meta.Sources = append(meta.Sources, source)
+	}
+	// Scripts.txt gives the version of the character database,
+	// confusables.txt the version of UTS #39 which follows it.
+	meta.UnicodeVersion = version
+	if meta.UnicodeVersion == "" {
+		meta.UnicodeVersion = meta.Sources[1].Version
+	}
+	if meta.UnicodeVersion == "" {
+		meta.UnicodeVersion = meta.Sources[0].Version
+	}
+	if meta.UnicodeVersion == "" {
+		return fmt.Errorf("no version in the headers of the files of %s, use -version", dir)
+	}
+
+	out := filepath.Join(dataDir, meta.UnicodeVersion)
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(out, "confusables.json"), confusables); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(out, "categories.json"), data); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(out, "script_extensions.json"), extensions); err != nil {
+		return err
+	}
+	return writeJSON(filepath.Join(out, "metadata.json"), meta)
+}
+
+// readSourceFile returns the checksum of the UCD file path along with the
+// version and date from its header, when present: data files start with
+// lines like "# Scripts-11.0.0.txt" and "# Date: 2018-02-21, 05:34:04 GMT",
+// confusables.txt has a "# Version: 11.0.0" line instead of the version in
+// its name. UnicodeData.txt has no header.
+func readSourceFile(path string) (sourceFile, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return sourceFile{}, err
+	}
+
+	sum := sha256.Sum256(b)
+	source := sourceFile{
+		Name:   filepath.Base(path),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+
+	base := strings.TrimSuffix(source.Name, ".txt")
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		text := strings.TrimPrefix(scanner.Text(), "\ufeff")
+		if !strings.HasPrefix(text, "#") {
+			break
+		}
+		text = strings.TrimSpace(strings.TrimPrefix(text, "#"))
+		switch {
+		case strings.HasPrefix(text, "Date:"):
+			source.Date = strings.TrimSpace(strings.TrimPrefix(text, "Date:"))
+		case strings.HasPrefix(text, "Version:"):
+			source.Version = strings.TrimSpace(strings.TrimPrefix(text, "Version:"))
+		case source.Version == "" && strings.HasPrefix(text, base+"-") && strings.HasSuffix(text, ".txt"):
+			source.Version = strings.TrimSuffix(strings.TrimPrefix(text, base+"-"), ".txt")
+		}
+	}
+	return source, scanner.Err()
+}
+
+// parseConfusables parses confusables.txt into a symmetric mapping: both
+// the source and its prototype list each other.
+func parseConfusables(path string, names []string) (map[string][]homoglyph, error) {
+	confusables := map[string][]homoglyph{}
+	err := parseUCDFile(path, func(fields []string) error {
+		if len(fields) < 2 {
+			return fmt.Errorf("invalid confusable: %v", fields)
+		}
+		source, err := parseCodePoints(fields[0])
+		if err != nil {
+			return err
+		}
+		target, err := parseCodePoints(fields[1])
+		if err != nil {
+			return err
+		}
+
+		sourceName, err := sequenceName(source, names)
+		if err != nil {
+			return err
+		}
+		targetName, err := sequenceName(target, names)
+		if err != nil {
+			return err
+		}
+
+		confusables[string(source)] = append(confusables[string(source)], homoglyph{C: string(target), N: targetName})
+		confusables[string(target)] = append(confusables[string(target)], homoglyph{C: string(source), N: sourceName})
+		return nil
+	})
+	return confusables, err
+}
+
+func sequenceName(sequence []rune, names []string) (string, error) {
+	sequenceNames := make([]string, len(sequence))
+	for i, r := range sequence {
+		if names[r] == "" {
+			return "", fmt.Errorf("no name for %04X", r)
+		}
+		sequenceNames[i] = names[r]
+	}
+	return strings.Join(sequenceNames, ", "), nil
+}
+
+// parseUnicodeData returns the names and general categories of all code
+// points from UnicodeData.txt, deriving the names of ideographs and Hangul
+// syllables listed as ranges.
+func parseUnicodeData(path string) ([]string, []string, error) {
+	names := make([]string, maxCodePoint+1)
+	categories := make([]string, maxCodePoint+1)
+	first := -1
+	err := parseUCDFile(path, func(fields []string) error {
+		if len(fields) < 3 {
+			return fmt.Errorf("invalid character data: %v", fields)
+		}
+		cp, err := parseCodePoint(fields[0])
+		if err != nil {
+			return err
+		}
+
+		name := fields[1]
+		switch {
+		case strings.HasSuffix(name, ", First>"):
+			first = int(cp)
+			return nil
+		case strings.HasSuffix(name, ", Last>"):
+			if first < 0 {
+				return fmt.Errorf("range end %04X without start", cp)
+			}
+			rangeName := strings.TrimSuffix(strings.TrimPrefix(name, "<"), ", Last>")
+			for r := first; r <= int(cp); r++ {
+				names[r] = derivedName(rune(r), rangeName)
+				categories[r] = fields[2]
+			}
+			first = -1
+			return nil
+		case strings.HasPrefix(name, "<"):
+			// controls have no name
+		default:
+			names[cp] = name
+		}
+		categories[cp] = fields[2]
+		return nil
+	})
+	return names, categories, err
+}
+
+var (
+	jamoL = []string{"G", "GG", "N", "D", "DD", "R", "M", "B", "BB", "S", "SS", "", "J", "JJ", "C", "K", "T", "P", "H"}
+	jamoV = []string{"A", "AE", "YA", "YAE", "EO", "E", "YEO", "YE", "O", "WA", "WAE", "OE", "YO", "U", "WEO", "WE", "WI", "YU", "EU", "YI", "I"}
+	jamoT = []string{"", "G", "GG", "GS", "N", "NJ", "NH", "D", "L", "LG", "LM", "LB", "LS", "LT", "LP", "LH", "M", "B", "BS", "S", "SS", "NG", "J", "C", "K", "T", "P", "H"}
+)
+
+// namePrefixes are the prefixes of the names derived by the rule NR2 of the
+// Unicode Standard section 4.8, by the start of the names of the ranges of
+// UnicodeData.txt they apply to, e.g. "Tangut Ideograph Supplement".
+var namePrefixes = []struct {
+	rangeName string
+	prefix    string
+}{
+	{"CJK Ideograph", "CJK UNIFIED IDEOGRAPH-"},
+	{"CJK Compatibility Ideograph", "CJK COMPATIBILITY IDEOGRAPH-"},
+	{"Tangut Ideograph", "TANGUT IDEOGRAPH-"},
+	{"Khitan Small Script", "KHITAN SMALL SCRIPT CHARACTER-"},
+	{"Nushu Character", "NUSHU CHARACTER-"},
+}
+
+// derivedName returns the name of r from the range rangeName, as per the
+// rules NR1 and NR2 of the Unicode Standard section 4.8, or "" for ranges
+// without names like the private use ones.
+func derivedName(r rune, rangeName string) string {
+	if rangeName == "Hangul Syllable" {
+		s := int(r - 0xAC00)
+		return "HANGUL SYLLABLE " + jamoL[s/(21*28)] + jamoV[s%(21*28)/28] + jamoT[s%28]
+	}
+	for _, p := range namePrefixes {
+		if strings.HasPrefix(rangeName, p.rangeName) {
+			return fmt.Sprintf("%s%04X", p.prefix, r)
+		}
+	}
+	return ""
+}
+
+// parseScripts returns the script alias of all code points from Scripts.txt.
+func parseScripts(path string) ([]string, error) {
+	scripts := make([]string, maxCodePoint+1)
+	err := parseUCDFile(path, func(fields []string) error {
+		if len(fields) < 2 {
+			return fmt.Errorf("invalid script: %v", fields)
+		}
+		first, last, err := parseCodePointRange(fields[0])
+		if err != nil {
+			return err
+		}
+		for r := first; r <= last; r++ {
+			scripts[r] = strings.ToUpper(fields[1])
+		}
+		return nil
+	})
+	return scripts, err
+}
+
+// parseScriptAliases returns the script aliases by ISO 15924 code from
+// PropertyValueAliases.txt.
+func parseScriptAliases(path string) (map[string]string, error) {
+	aliases := map[string]string{}
+	err := parseUCDFile(path, func(fields []string) error {
+		if len(fields) < 3 || fields[0] != "sc" {
+			return nil
+		}
+		alias := strings.ToUpper(fields[2])
+		aliases[fields[1]] = alias
+		for _, a := range fields[3:] {
+			aliases[a] = alias
+		}
+		return nil
+	})
+	return aliases, err
+}
+
+// parseScriptExtensions parses ScriptExtensions.txt, which refers to
+// scripts by ISO 15924 code.
+func parseScriptExtensions(path string, aliases map[string]string, order []string) (scriptExtensionsData, error) {
+	index := map[string]int{}
+	for i, a := range order {
+		index[a] = i
+	}
+
+	data := scriptExtensionsData{CodePointsRanges: []scriptExtensionsRange{}}
+	err := parseUCDFile(path, func(fields []string) error {
+		if len(fields) < 2 {
+			return fmt.Errorf("invalid script extensions: %v", fields)
+		}
+		first, last, err := parseCodePointRange(fields[0])
+		if err != nil {
+			return err
+		}
+
+		scripts := []string{}
+		for _, code := range strings.Fields(fields[1]) {
+			alias, ok := aliases[code]
+			if !ok {
+				return fmt.Errorf("unknown script code %s", code)
+			}
+			scripts = append(scripts, alias)
+		}
+		sort.Slice(scripts, func(i, j int) bool {
+			return index[scripts[i]] < index[scripts[j]]
+		})
+
+		data.CodePointsRanges = append(data.CodePointsRanges, scriptExtensionsRange{
+			First:   int(first),
+			Last:    int(last),
+			Scripts: scripts,
+		})
+		return nil
+	})
+	if err != nil {
+		return data, err
+	}
+
+	sort.Slice(data.CodePointsRanges, func(i, j int) bool {
+		return data.CodePointsRanges[i].First < data.CodePointsRanges[j].First
+	})
+	return data, nil
+}
+
+// buildCategoryData merges consecutive code points with the same script and
+// category into ranges. The L category of previous, which collapsed the
+// cased letters in the data inherited from the Python library, is taken
+// over by Lu.
+func buildCategoryData(scripts []string, categories []string, previous categoryData) categoryData {
+	data := categoryData{
+		ISO15924aliases:  append([]string{}, previous.ISO15924aliases...),
+		Categories:       append([]string{}, previous.Categories...),
+		CodePointsRanges: [][]int{},
+	}
+	if _, ok := indexOf(data.Categories)["Lu"]; !ok {
+		for i, c := range data.Categories {
+			if c == "L" {
+				data.Categories[i] = "Lu"
+			}
+		}
+	}
+	aliasIndex := indexOf(data.ISO15924aliases)
+	categoryIndex := indexOf(data.Categories)
+
+	for r := 0; r <= maxCodePoint; r++ {
+		if scripts[r] == "" || categories[r] == "" {
+			continue
+		}
+		category := categories[r]
+
+		a, ok := aliasIndex[scripts[r]]
+		if !ok {
+			a = len(data.ISO15924aliases)
+			aliasIndex[scripts[r]] = a
+			data.ISO15924aliases = append(data.ISO15924aliases, scripts[r])
+		}
+		c, ok := categoryIndex[category]
+		if !ok {
+			c = len(data.Categories)
+			categoryIndex[category] = c
+			data.Categories = append(data.Categories, category)
+		}
+
+		if n := len(data.CodePointsRanges); n > 0 {
+			last := data.CodePointsRanges[n-1]
+			if last[1] == r-1 && last[2] == a && last[3] == c {
+				last[1] = r
+				continue
+			}
+		}
+		data.CodePointsRanges = append(data.CodePointsRanges, []int{r, r, a, c})
+	}
+	return data
+}
+
+func indexOf(values []string) map[string]int {
+	index := map[string]int{}
+	for i, v := range values {
+		index[v] = i
+	}
+	return index
+}
+
+// parseUCDFile calls fn with the trimmed fields of every data line of the
+// UCD file path.
+func parseUCDFile(path string, fn func(fields []string) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimPrefix(scanner.Text(), "\ufeff")
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		fields := strings.Split(text, ";")
+		for i, f := range fields {
+			fields[i] = strings.TrimSpace(f)
+		}
+		if err := fn(fields); err != nil {
+			return fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func parseCodePoint(s string) (rune, error) {
+	cp, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || cp > maxCodePoint {
+		return 0, fmt.Errorf("invalid code point %q", s)
+	}
+	return rune(cp), nil
+}
+
+// parseCodePoints parses a space separated sequence of code points.
+func parseCodePoints(s string) ([]rune, error) {
+	sequence := []rune{}
+	for _, f := range strings.Fields(s) {
+		cp, err := parseCodePoint(f)
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, cp)
+	}
+	if len(sequence) == 0 {
+		return nil, fmt.Errorf("empty code points sequence")
+	}
+	return sequence, nil
+}
+
+// parseCodePointRange parses a single code point or a range like 0000..001F.
+func parseCodePointRange(s string) (rune, rune, error) {
+	parts := strings.SplitN(s, "..", 2)
+	first, err := parseCodePoint(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(parts) == 1 {
+		return first, first, nil
+	}
+	last, err := parseCodePoint(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return first, last, nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseUnicodeData(t *testing.T) {
+	names, categories, err := parseUnicodeData(filepath.Join("testdata", "UnicodeData.txt"))
+	if err != nil {
+		t.Fatalf("unexpected error, expected: %v, actual: %v\n", nil, err)
+	}
+
+	cases := []struct {
+		cp       rune
+		name     string
+		category string
+	}{
+		{0x0000, "", "Cc"},
+		{0x0041, "LATIN CAPITAL LETTER A", "Lu"},
+		{0x0042, "", ""},
+		{0x3400, "CJK UNIFIED IDEOGRAPH-3400", "Lo"},
+		{0x3401, "CJK UNIFIED IDEOGRAPH-3401", "Lo"},
+		{0x3402, "CJK UNIFIED IDEOGRAPH-3402", "Lo"},
+		{0x3403, "", ""},
+		{0xAC00, "HANGUL SYLLABLE GA", "Lo"},
+		{0xAC02, "HANGUL SYLLABLE GAGG", "Lo"},
+		{0xE000, "", "Co"},
+		{0xE001, "", "Co"},
+		{0x18D00, "TANGUT IDEOGRAPH-18D00", "Lo"},
+		{0x18D01, "TANGUT IDEOGRAPH-18D01", "Lo"},
+	}
+
+	for _, c := range cases {
+		if names[c.cp] != c.name || categories[c.cp] != c.category {
+			t.Errorf("unexpected character data, code point: %04X, expected: %q %q, actual: %q %q\n", c.cp, c.name, c.category, names[c.cp], categories[c.cp])
+		}
+	}
+}
+
+func TestDerivedName(t *testing.T) {
+	cases := []struct {
+		r         rune
+		rangeName string
+		name      string
+	}{
+		{0x4E00, "CJK Ideograph", "CJK UNIFIED IDEOGRAPH-4E00"},
+		{0x20000, "CJK Ideograph Extension B", "CJK UNIFIED IDEOGRAPH-20000"},
+		{0x17000, "Tangut Ideograph", "TANGUT IDEOGRAPH-17000"},
+		{0x18D08, "Tangut Ideograph Supplement", "TANGUT IDEOGRAPH-18D08"},
+		{0x18B00, "Khitan Small Script", "KHITAN SMALL SCRIPT CHARACTER-18B00"},
+		{0x1B170, "Nushu Character", "NUSHU CHARACTER-1B170"},
+		{0xF900, "CJK Compatibility Ideograph", "CJK COMPATIBILITY IDEOGRAPH-F900"},
+		{0xAC00, "Hangul Syllable", "HANGUL SYLLABLE GA"},
+		{0xAC01, "Hangul Syllable", "HANGUL SYLLABLE GAG"},
+		{0xD4DB, "Hangul Syllable", "HANGUL SYLLABLE PWILH"},
+		{0xD7A3, "Hangul Syllable", "HANGUL SYLLABLE HIH"},
+		{0xE000, "Private Use", ""},
+		{0xD800, "Non Private Use High Surrogate", ""},
+	}
+
+	for _, c := range cases {
+		if name := derivedName(c.r, c.rangeName); name != c.name {
+			t.Errorf("unexpected name, code point: %04X, range: %v, expected: %v, actual: %v\n", c.r, c.rangeName, c.name, name)
+		}
+	}
+}
+
+func TestParseConfusables(t *testing.T) {
+	names, _, err := parseUnicodeData(filepath.Join("testdata", "UnicodeData.txt"))
+	if err != nil {
+		t.Fatalf("unexpected error, expected: %v, actual: %v\n", nil, err)
+	}
+	confusables, err := parseConfusables(filepath.Join("testdata", "confusables.txt"), names)
+	if err != nil {
+		t.Fatalf("unexpected error, expected: %v, actual: %v\n", nil, err)
+	}
+
+	// sources and prototypes list each other, the names of sequences are
+	// the names of their characters
+	expected := map[string][]homoglyph{
+		"׀":  {{C: "|", N: "VERTICAL LINE"}},
+		"|":  {{C: "׀", N: "HEBREW PUNCTUATION PASEQ"}},
+		"rn": {{C: "m", N: "LATIN SMALL LETTER M"}},
+		"m":  {{C: "rn", N: "LATIN SMALL LETTER R, LATIN SMALL LETTER N"}},
+	}
+	if !reflect.DeepEqual(confusables, expected) {
+		t.Errorf("unexpected confusables, expected: %v, actual: %v\n", expected, confusables)
+	}
+
+	// characters without names are rejected
+	if _, err := parseConfusables(filepath.Join("testdata", "confusables.txt"), make([]string, maxCodePoint+1)); err == nil {
+		t.Errorf("unexpected error, expected: an error, actual: %v\n", err)
+	}
+}
+
+func TestReadSourceFile(t *testing.T) {
+	cases := []struct {
+		name    string
+		version string
+		date    string
+	}{
+		{"confusables.txt", "11.0.0", "2000-01-01, 00:00:00 GMT"},
+		{"Scripts.txt", "11.0.0", "2000-01-01, 00:00:00 GMT"},
+		{"UnicodeData.txt", "", ""},
+	}
+
+	for _, c := range cases {
+		source, err := readSourceFile(filepath.Join("testdata", c.name))
+		if err != nil {
+			t.Errorf("unexpected error, file: %v, expected: %v, actual: %v\n", c.name, nil, err)
+			continue
+		}
+		if source.Name != c.name || source.Version != c.version || source.Date != c.date {
+			t.Errorf("unexpected source file, expected: %v %v %v, actual: %v %v %v\n", c.name, c.version, c.date, source.Name, source.Version, source.Date)
+		}
+		if len(source.SHA256) != 64 {
+			t.Errorf("unexpected checksum, file: %v, actual: %q\n", c.name, source.SHA256)
+		}
+	}
+}
+
+func TestBuildCategoryData(t *testing.T) {
+	scripts := make([]string, maxCodePoint+1)
+	categories := make([]string, maxCodePoint+1)
+	for r, s := range map[rune][2]string{
+		0x41: {"LATIN", "Lu"}, 0x42: {"LATIN", "Lu"},
+		0x61: {"LATIN", "Ll"}, 0x62: {"LATIN", "Ll"},
+		0x30:  {"COMMON", "Nd"},
+		0x3B1: {"GREEK", "Ll"},
+	} {
+		scripts[r], categories[r] = s[0], s[1]
+	}
+
+	cases := []struct {
+		name     string
+		previous categoryData
+		expected categoryData
+	}{
+		{"empty", categoryData{}, categoryData{
+			ISO15924aliases:  []string{"COMMON", "LATIN", "GREEK"},
+			Categories:       []string{"Nd", "Lu", "Ll"},
+			CodePointsRanges: [][]int{{0x30, 0x30, 0, 0}, {0x41, 0x42, 1, 1}, {0x61, 0x62, 1, 2}, {0x3B1, 0x3B1, 2, 2}},
+		}},
+		// the collapsed L category of the Python library is taken over by
+		// Lu, in place
+		{"collapsed letters", categoryData{
+			ISO15924aliases: []string{"LATIN", "COMMON"},
+			Categories:      []string{"L", "Nd"},
+		}, categoryData{
+			ISO15924aliases:  []string{"LATIN", "COMMON", "GREEK"},
+			Categories:       []string{"Lu", "Nd", "Ll"},
+			CodePointsRanges: [][]int{{0x30, 0x30, 1, 1}, {0x41, 0x42, 0, 0}, {0x61, 0x62, 0, 2}, {0x3B1, 0x3B1, 2, 2}},
+		}},
+		{"cased letters", categoryData{
+			ISO15924aliases: []string{"LATIN"},
+			Categories:      []string{"Ll", "Lu", "L"},
+		}, categoryData{
+			ISO15924aliases:  []string{"LATIN", "COMMON", "GREEK"},
+			Categories:       []string{"Ll", "Lu", "L", "Nd"},
+			CodePointsRanges: [][]int{{0x30, 0x30, 1, 3}, {0x41, 0x42, 0, 1}, {0x61, 0x62, 0, 0}, {0x3B1, 0x3B1, 2, 0}},
+		}},
+	}
+
+	for _, c := range cases {
+		data := buildCategoryData(scripts, categories, c.previous)
+		if !reflect.DeepEqual(data, c.expected) {
+			t.Errorf("unexpected categories data, case: %v, expected: %v, actual: %v\n", c.name, c.expected, data)
+		}
+	}
+}
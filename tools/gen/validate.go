@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxReported is the number of problems listed in a validation report.
+const maxReported = 20
+
+// validateDataset checks the invariants the package relies on: code points
+// ranges sorted without overlaps and with valid indexes, script extensions
+// ranges likewise, and symmetric confusables made of assigned characters.
+// Gaps between code points ranges are unassigned code points, so they must
+// not contain confusables or script extensions.
+func validateDataset(d dataset) error {
+	problems := []string{}
+	report := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	data := d.categories
+	for i, r := range data.CodePointsRanges {
+		if len(r) != 4 {
+			report("code points range %d: %d values instead of 4", i, len(r))
+			continue
+		}
+		if r[0] > r[1] || r[1] > maxCodePoint {
+			report("code points range %04X..%04X: invalid bounds", r[0], r[1])
+		}
+		if i > 0 && len(data.CodePointsRanges[i-1]) == 4 {
+			previous := data.CodePointsRanges[i-1]
+			if previous[0] > r[0] {
+				report("code points range %04X..%04X: not sorted after %04X..%04X", r[0], r[1], previous[0], previous[1])
+			} else if previous[1] >= r[0] {
+				report("code points range %04X..%04X: overlaps %04X..%04X", r[0], r[1], previous[0], previous[1])
+			}
+		}
+		if r[2] < 0 || r[2] >= len(data.ISO15924aliases) {
+			report("code points range %04X..%04X: script %d out of iso_15924_aliases", r[0], r[1], r[2])
+		}
+		if r[3] < 0 || r[3] >= len(data.Categories) {
+			report("code points range %04X..%04X: category %d out of categories", r[0], r[1], r[3])
+		}
+	}
+
+	for i, set := range data.ScriptExtensions {
+		for j, s := range set {
+			if s < 0 || s >= len(data.ISO15924aliases) {
+				report("script extensions %d: script %d out of iso_15924_aliases", i, s)
+			}
+			if j > 0 && set[j-1] >= s {
+				report("script extensions %d: scripts not sorted", i)
+			}
+		}
+	}
+	for i, r := range data.ScriptExtensionsRanges {
+		if r[0] > r[1] {
+			report("script extensions range %04X..%04X: invalid bounds", r[0], r[1])
+		}
+		if i > 0 && data.ScriptExtensionsRanges[i-1][1] >= r[0] {
+			report("script extensions range %04X..%04X: not sorted or overlapping", r[0], r[1])
+		}
+		if r[2] < 0 || r[2] >= len(data.ScriptExtensions) {
+			report("script extensions range %04X..%04X: set %d out of script extensions", r[0], r[1], r[2])
+		}
+		for cp := r[0]; cp <= r[1]; cp++ {
+			if !assigned(data, rune(cp)) {
+				report("script extensions range %04X..%04X: unassigned code point %04X", r[0], r[1], cp)
+				break
+			}
+		}
+	}
+
+	for k, homoglyphs := range d.confusables {
+		if !validString(k) {
+			report("confusable %+q: invalid string", k)
+			continue
+		}
+		for _, chr := range trimLRM(k) {
+			if !assigned(data, chr) {
+				report("confusable %+q: unassigned code point %04X", k, chr)
+			}
+		}
+		if len(homoglyphs) == 0 {
+			report("confusable %+q: no homoglyphs", k)
+		}
+		seen := map[string]bool{}
+		for _, h := range homoglyphs {
+			if !validString(h.C) {
+				report("confusable %+q: invalid homoglyph %+q", k, h.C)
+				continue
+			}
+			if seen[h.C] {
+				report("confusable %+q: duplicate homoglyph %+q", k, h.C)
+			}
+			seen[h.C] = true
+			if !lists(d.confusables[h.C], k) {
+				report("confusable %+q: missing from the homoglyphs of %+q", k, h.C)
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	if len(problems) > maxReported {
+		problems = append(problems[:maxReported], fmt.Sprintf("and %d more", len(problems)-maxReported))
+	}
+	return fmt.Errorf("invalid dataset %s:\n  %s", d.Version, strings.Join(problems, "\n  "))
+}
+
+// assigned checks if chr is in a code points range of data.
+func assigned(data categoryData, chr rune) bool {
+	ranges := data.CodePointsRanges
+	l, r := 0, len(ranges)-1
+	for l <= r {
+		m := (l + r) / 2
+		if len(ranges[m]) < 2 {
+			return false
+		}
+		if int(chr) < ranges[m][0] {
+			r = m - 1
+		} else if int(chr) > ranges[m][1] {
+			l = m + 1
+		} else {
+			return true
+		}
+	}
+	return false
+}
+
+func validString(str string) bool {
+	return str != "" && utf8.ValidString(str)
+}
+
+func lists(homoglyphs []homoglyph, str string) bool {
+	for _, h := range homoglyphs {
+		if h.C == str {
+			return true
+		}
+	}
+	return false
+}
+
+// trimLRM removes the LEFT-TO-RIGHT MARKs wrapped around right-to-left
+// characters in the confusables data.
+func trimLRM(str string) string {
+	return strings.Trim(str, "\u200e")
+}
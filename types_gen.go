@@ -0,0 +1,390 @@
+// Code generated by go generate; DO NOT EDIT.
+package confusablehomoglyphs
+
+// Scripts, identified by their aliases.
+const (
+	ScriptUnknown Script = iota
+	ScriptCommon
+	ScriptLatin
+	ScriptGreek
+	ScriptCyrillic
+	ScriptArmenian
+	ScriptHebrew
+	ScriptArabic
+	ScriptSyriac
+	ScriptThaana
+	ScriptDevanagari
+	ScriptBengali
+	ScriptGurmukhi
+	ScriptGujarati
+	ScriptOriya
+	ScriptTamil
+	ScriptTelugu
+	ScriptKannada
+	ScriptMalayalam
+	ScriptSinhala
+	ScriptThai
+	ScriptLao
+	ScriptTibetan
+	ScriptMyanmar
+	ScriptGeorgian
+	ScriptHangul
+	ScriptEthiopic
+	ScriptCherokee
+	ScriptCanadianAboriginal
+	ScriptOgham
+	ScriptRunic
+	ScriptKhmer
+	ScriptMongolian
+	ScriptHiragana
+	ScriptKatakana
+	ScriptBopomofo
+	ScriptHan
+	ScriptYi
+	ScriptOldItalic
+	ScriptGothic
+	ScriptDeseret
+	ScriptInherited
+	ScriptTagalog
+	ScriptHanunoo
+	ScriptBuhid
+	ScriptTagbanwa
+	ScriptLimbu
+	ScriptTaiLe
+	ScriptLinearB
+	ScriptUgaritic
+	ScriptShavian
+	ScriptOsmanya
+	ScriptCypriot
+	ScriptBraille
+	ScriptBuginese
+	ScriptCoptic
+	ScriptNewTaiLue
+	ScriptGlagolitic
+	ScriptTifinagh
+	ScriptSylotiNagri
+	ScriptOldPersian
+	ScriptKharoshthi
+	ScriptBalinese
+	ScriptCuneiform
+	ScriptPhoenician
+	ScriptPhagsPa
+	ScriptNko
+	ScriptSundanese
+	ScriptLepcha
+	ScriptOlChiki
+	ScriptVai
+	ScriptSaurashtra
+	ScriptKayahLi
+	ScriptRejang
+	ScriptLycian
+	ScriptCarian
+	ScriptLydian
+	ScriptCham
+	ScriptTaiTham
+	ScriptTaiViet
+	ScriptAvestan
+	ScriptEgyptianHieroglyphs
+	ScriptSamaritan
+	ScriptLisu
+	ScriptBamum
+	ScriptJavanese
+	ScriptMeeteiMayek
+	ScriptImperialAramaic
+	ScriptOldSouthArabian
+	ScriptInscriptionalParthian
+	ScriptInscriptionalPahlavi
+	ScriptOldTurkic
+	ScriptKaithi
+	ScriptBatak
+	ScriptBrahmi
+	ScriptMandaic
+	ScriptChakma
+	ScriptMeroiticCursive
+	ScriptMeroiticHieroglyphs
+	ScriptMiao
+	ScriptSharada
+	ScriptSoraSompeng
+	ScriptTakri
+	ScriptCaucasianAlbanian
+	ScriptBassaVah
+	ScriptDuployan
+	ScriptElbasan
+	ScriptGrantha
+	ScriptPahawhHmong
+	ScriptKhojki
+	ScriptLinearA
+	ScriptMahajani
+	ScriptManichaean
+	ScriptMendeKikakui
+	ScriptModi
+	ScriptMro
+	ScriptOldNorthArabian
+	ScriptNabataean
+	ScriptPalmyrene
+	ScriptPauCinHau
+	ScriptOldPermic
+	ScriptPsalterPahlavi
+	ScriptSiddham
+	ScriptKhudawadi
+	ScriptTirhuta
+	ScriptWarangCiti
+	ScriptAhom
+	ScriptAnatolianHieroglyphs
+	ScriptHatran
+	ScriptMultani
+	ScriptOldHungarian
+	ScriptSignwriting
+	ScriptAdlam
+	ScriptBhaiksuki
+	ScriptMarchen
+	ScriptNewa
+	ScriptOsage
+	ScriptTangut
+	ScriptMasaramGondi
+	ScriptNushu
+	ScriptSoyombo
+	ScriptZanabazarSquare
+	ScriptDogra
+	ScriptGunjalaGondi
+	ScriptMakasar
+	ScriptMedefaidrin
+	ScriptHanifiRohingya
+	ScriptSogdian
+	ScriptOldSogdian
+	ScriptHanWithBopomofo
+	ScriptJapanese
+	ScriptKorean
+)
+
+var scriptNames = [...]string{
+	ScriptUnknown:               "Unknown",
+	ScriptCommon:                "COMMON",
+	ScriptLatin:                 "LATIN",
+	ScriptGreek:                 "GREEK",
+	ScriptCyrillic:              "CYRILLIC",
+	ScriptArmenian:              "ARMENIAN",
+	ScriptHebrew:                "HEBREW",
+	ScriptArabic:                "ARABIC",
+	ScriptSyriac:                "SYRIAC",
+	ScriptThaana:                "THAANA",
+	ScriptDevanagari:            "DEVANAGARI",
+	ScriptBengali:               "BENGALI",
+	ScriptGurmukhi:              "GURMUKHI",
+	ScriptGujarati:              "GUJARATI",
+	ScriptOriya:                 "ORIYA",
+	ScriptTamil:                 "TAMIL",
+	ScriptTelugu:                "TELUGU",
+	ScriptKannada:               "KANNADA",
+	ScriptMalayalam:             "MALAYALAM",
+	ScriptSinhala:               "SINHALA",
+	ScriptThai:                  "THAI",
+	ScriptLao:                   "LAO",
+	ScriptTibetan:               "TIBETAN",
+	ScriptMyanmar:               "MYANMAR",
+	ScriptGeorgian:              "GEORGIAN",
+	ScriptHangul:                "HANGUL",
+	ScriptEthiopic:              "ETHIOPIC",
+	ScriptCherokee:              "CHEROKEE",
+	ScriptCanadianAboriginal:    "CANADIAN_ABORIGINAL",
+	ScriptOgham:                 "OGHAM",
+	ScriptRunic:                 "RUNIC",
+	ScriptKhmer:                 "KHMER",
+	ScriptMongolian:             "MONGOLIAN",
+	ScriptHiragana:              "HIRAGANA",
+	ScriptKatakana:              "KATAKANA",
+	ScriptBopomofo:              "BOPOMOFO",
+	ScriptHan:                   "HAN",
+	ScriptYi:                    "YI",
+	ScriptOldItalic:             "OLD_ITALIC",
+	ScriptGothic:                "GOTHIC",
+	ScriptDeseret:               "DESERET",
+	ScriptInherited:             "INHERITED",
+	ScriptTagalog:               "TAGALOG",
+	ScriptHanunoo:               "HANUNOO",
+	ScriptBuhid:                 "BUHID",
+	ScriptTagbanwa:              "TAGBANWA",
+	ScriptLimbu:                 "LIMBU",
+	ScriptTaiLe:                 "TAI_LE",
+	ScriptLinearB:               "LINEAR_B",
+	ScriptUgaritic:              "UGARITIC",
+	ScriptShavian:               "SHAVIAN",
+	ScriptOsmanya:               "OSMANYA",
+	ScriptCypriot:               "CYPRIOT",
+	ScriptBraille:               "BRAILLE",
+	ScriptBuginese:              "BUGINESE",
+	ScriptCoptic:                "COPTIC",
+	ScriptNewTaiLue:             "NEW_TAI_LUE",
+	ScriptGlagolitic:            "GLAGOLITIC",
+	ScriptTifinagh:              "TIFINAGH",
+	ScriptSylotiNagri:           "SYLOTI_NAGRI",
+	ScriptOldPersian:            "OLD_PERSIAN",
+	ScriptKharoshthi:            "KHAROSHTHI",
+	ScriptBalinese:              "BALINESE",
+	ScriptCuneiform:             "CUNEIFORM",
+	ScriptPhoenician:            "PHOENICIAN",
+	ScriptPhagsPa:               "PHAGS_PA",
+	ScriptNko:                   "NKO",
+	ScriptSundanese:             "SUNDANESE",
+	ScriptLepcha:                "LEPCHA",
+	ScriptOlChiki:               "OL_CHIKI",
+	ScriptVai:                   "VAI",
+	ScriptSaurashtra:            "SAURASHTRA",
+	ScriptKayahLi:               "KAYAH_LI",
+	ScriptRejang:                "REJANG",
+	ScriptLycian:                "LYCIAN",
+	ScriptCarian:                "CARIAN",
+	ScriptLydian:                "LYDIAN",
+	ScriptCham:                  "CHAM",
+	ScriptTaiTham:               "TAI_THAM",
+	ScriptTaiViet:               "TAI_VIET",
+	ScriptAvestan:               "AVESTAN",
+	ScriptEgyptianHieroglyphs:   "EGYPTIAN_HIEROGLYPHS",
+	ScriptSamaritan:             "SAMARITAN",
+	ScriptLisu:                  "LISU",
+	ScriptBamum:                 "BAMUM",
+	ScriptJavanese:              "JAVANESE",
+	ScriptMeeteiMayek:           "MEETEI_MAYEK",
+	ScriptImperialAramaic:       "IMPERIAL_ARAMAIC",
+	ScriptOldSouthArabian:       "OLD_SOUTH_ARABIAN",
+	ScriptInscriptionalParthian: "INSCRIPTIONAL_PARTHIAN",
+	ScriptInscriptionalPahlavi:  "INSCRIPTIONAL_PAHLAVI",
+	ScriptOldTurkic:             "OLD_TURKIC",
+	ScriptKaithi:                "KAITHI",
+	ScriptBatak:                 "BATAK",
+	ScriptBrahmi:                "BRAHMI",
+	ScriptMandaic:               "MANDAIC",
+	ScriptChakma:                "CHAKMA",
+	ScriptMeroiticCursive:       "MEROITIC_CURSIVE",
+	ScriptMeroiticHieroglyphs:   "MEROITIC_HIEROGLYPHS",
+	ScriptMiao:                  "MIAO",
+	ScriptSharada:               "SHARADA",
+	ScriptSoraSompeng:           "SORA_SOMPENG",
+	ScriptTakri:                 "TAKRI",
+	ScriptCaucasianAlbanian:     "CAUCASIAN_ALBANIAN",
+	ScriptBassaVah:              "BASSA_VAH",
+	ScriptDuployan:              "DUPLOYAN",
+	ScriptElbasan:               "ELBASAN",
+	ScriptGrantha:               "GRANTHA",
+	ScriptPahawhHmong:           "PAHAWH_HMONG",
+	ScriptKhojki:                "KHOJKI",
+	ScriptLinearA:               "LINEAR_A",
+	ScriptMahajani:              "MAHAJANI",
+	ScriptManichaean:            "MANICHAEAN",
+	ScriptMendeKikakui:          "MENDE_KIKAKUI",
+	ScriptModi:                  "MODI",
+	ScriptMro:                   "MRO",
+	ScriptOldNorthArabian:       "OLD_NORTH_ARABIAN",
+	ScriptNabataean:             "NABATAEAN",
+	ScriptPalmyrene:             "PALMYRENE",
+	ScriptPauCinHau:             "PAU_CIN_HAU",
+	ScriptOldPermic:             "OLD_PERMIC",
+	ScriptPsalterPahlavi:        "PSALTER_PAHLAVI",
+	ScriptSiddham:               "SIDDHAM",
+	ScriptKhudawadi:             "KHUDAWADI",
+	ScriptTirhuta:               "TIRHUTA",
+	ScriptWarangCiti:            "WARANG_CITI",
+	ScriptAhom:                  "AHOM",
+	ScriptAnatolianHieroglyphs:  "ANATOLIAN_HIEROGLYPHS",
+	ScriptHatran:                "HATRAN",
+	ScriptMultani:               "MULTANI",
+	ScriptOldHungarian:          "OLD_HUNGARIAN",
+	ScriptSignwriting:           "SIGNWRITING",
+	ScriptAdlam:                 "ADLAM",
+	ScriptBhaiksuki:             "BHAIKSUKI",
+	ScriptMarchen:               "MARCHEN",
+	ScriptNewa:                  "NEWA",
+	ScriptOsage:                 "OSAGE",
+	ScriptTangut:                "TANGUT",
+	ScriptMasaramGondi:          "MASARAM_GONDI",
+	ScriptNushu:                 "NUSHU",
+	ScriptSoyombo:               "SOYOMBO",
+	ScriptZanabazarSquare:       "ZANABAZAR_SQUARE",
+	ScriptDogra:                 "DOGRA",
+	ScriptGunjalaGondi:          "GUNJALA_GONDI",
+	ScriptMakasar:               "MAKASAR",
+	ScriptMedefaidrin:           "MEDEFAIDRIN",
+	ScriptHanifiRohingya:        "HANIFI_ROHINGYA",
+	ScriptSogdian:               "SOGDIAN",
+	ScriptOldSogdian:            "OLD_SOGDIAN",
+	ScriptHanWithBopomofo:       "HAN_WITH_BOPOMOFO",
+	ScriptJapanese:              "JAPANESE",
+	ScriptKorean:                "KOREAN",
+}
+
+// General categories, identified by their short names.
+const (
+	CategoryUnknown GeneralCategory = iota
+	CategoryCc
+	CategoryZs
+	CategoryPo
+	CategorySc
+	CategoryPs
+	CategoryPe
+	CategorySm
+	CategoryPd
+	CategoryNd
+	CategorySk
+	CategoryPc
+	CategorySo
+	CategoryPi
+	CategoryCf
+	CategoryNo
+	CategoryLu
+	CategoryPf
+	CategoryLm
+	CategoryMc
+	CategoryLo
+	CategoryZl
+	CategoryZp
+	CategoryNl
+	CategoryMn
+	CategoryMe
+	CategoryLl
+	CategoryLt
+	CategoryL
+	CategoryM
+	CategoryN
+	CategoryP
+	CategoryS
+	CategoryZ
+	CategoryC
+)
+
+var categoryNames = [...]string{
+	CategoryUnknown: "Zzzz",
+	CategoryCc:      "Cc",
+	CategoryZs:      "Zs",
+	CategoryPo:      "Po",
+	CategorySc:      "Sc",
+	CategoryPs:      "Ps",
+	CategoryPe:      "Pe",
+	CategorySm:      "Sm",
+	CategoryPd:      "Pd",
+	CategoryNd:      "Nd",
+	CategorySk:      "Sk",
+	CategoryPc:      "Pc",
+	CategorySo:      "So",
+	CategoryPi:      "Pi",
+	CategoryCf:      "Cf",
+	CategoryNo:      "No",
+	CategoryLu:      "Lu",
+	CategoryPf:      "Pf",
+	CategoryLm:      "Lm",
+	CategoryMc:      "Mc",
+	CategoryLo:      "Lo",
+	CategoryZl:      "Zl",
+	CategoryZp:      "Zp",
+	CategoryNl:      "Nl",
+	CategoryMn:      "Mn",
+	CategoryMe:      "Me",
+	CategoryLl:      "Ll",
+	CategoryLt:      "Lt",
+	CategoryL:       "L",
+	CategoryM:       "M",
+	CategoryN:       "N",
+	CategoryP:       "P",
+	CategoryS:       "S",
+	CategoryZ:       "Z",
+	CategoryC:       "C",
+}